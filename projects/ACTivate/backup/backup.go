@@ -0,0 +1,120 @@
+// Package backup bundles the node keystore, the node password and the app
+// state into a single file encrypted with a backup passphrase.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"activate/secrets"
+)
+
+const (
+	// FileExtension is the suggested extension of backup files.
+	FileExtension = ".activate-backup"
+
+	bundleVersion = 1
+	keysDir       = "keys"
+	keyExtension  = ".key"
+)
+
+// ErrKeystoreExists is returned when restoring onto an install that already
+// has a keystore.
+var ErrKeystoreExists = errors.New("a keystore already exists, restore needs a fresh install")
+
+// Bundle is the decrypted content of a backup.
+type Bundle struct {
+	Version      int               `json:"version"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	NodePassword string            `json:"nodePassword"`
+	Keys         map[string][]byte `json:"keys"`
+	State        map[string]string `json:"state"`
+}
+
+// New collects the keystore files of dataDir into a bundle.
+func New(dataDir, nodePassword string, state map[string]string) (*Bundle, error) {
+	entries, err := os.ReadDir(filepath.Join(dataDir, keysDir))
+	if err != nil {
+		return nil, fmt.Errorf("read keystore: %w", err)
+	}
+
+	keys := make(map[string][]byte)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), keyExtension) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dataDir, keysDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read key %s: %w", e.Name(), err)
+		}
+		keys[e.Name()] = data
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("keystore is empty")
+	}
+
+	return &Bundle{
+		Version:      bundleVersion,
+		CreatedAt:    time.Now().UTC(),
+		NodePassword: nodePassword,
+		Keys:         keys,
+		State:        state,
+	}, nil
+}
+
+// Encrypt encodes the bundle and seals it with the backup passphrase.
+func (b *Bundle) Encrypt(passphrase string) ([]byte, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("encode backup: %w", err)
+	}
+	return secrets.Seal(passphrase, data)
+}
+
+// Decrypt opens a backup file with the backup passphrase.
+func Decrypt(data []byte, passphrase string) (*Bundle, error) {
+	plaintext, err := secrets.Unseal(passphrase, data)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(plaintext, b); err != nil {
+		return nil, fmt.Errorf("decode backup: %w", err)
+	}
+	if b.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	if len(b.Keys) == 0 {
+		return nil, errors.New("backup has no keys")
+	}
+	return b, nil
+}
+
+// RestoreKeys writes the keystore files into dataDir. It refuses to replace
+// an existing keystore.
+func (b *Bundle) RestoreKeys(dataDir string) error {
+	dir := filepath.Join(dataDir, keysDir)
+	for name := range b.Keys {
+		if name != filepath.Base(name) || !strings.HasSuffix(name, keyExtension) {
+			return fmt.Errorf("invalid key name %q in backup", name)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return ErrKeystoreExists
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for name, data := range b.Keys {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return fmt.Errorf("write key %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"activate/secrets"
+)
+
+// newDataDir returns a data directory with a keystore of the given files.
+func newDataDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, keysDir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, keysDir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestNew(t *testing.T) {
+	dir := newDataDir(t, map[string]string{"swarm.key": "swarm", "libp2p_v2.key": "libp2p", "notes.txt": "skipped"})
+
+	b, err := New(dir, "node password", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Keys) != 2 || string(b.Keys["swarm.key"]) != "swarm" || string(b.Keys["libp2p_v2.key"]) != "libp2p" {
+		t.Errorf("keys = %q, want the two key files", b.Keys)
+	}
+	if b.Version != bundleVersion || b.NodePassword != "node password" || b.State["k"] != "v" {
+		t.Errorf("bundle = %+v", b)
+	}
+
+	if _, err := New(newDataDir(t, map[string]string{"notes.txt": "skipped"}), "", nil); err == nil {
+		t.Error("backed up an empty keystore")
+	}
+	if _, err := New(t.TempDir(), "", nil); err == nil {
+		t.Error("backed up a missing keystore")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	b, err := New(newDataDir(t, map[string]string{"swarm.key": "swarm"}), "node password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.Encrypt("backup passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("node password")) {
+		t.Error("the backup contains the node password in clear")
+	}
+
+	got, err := Decrypt(data, "backup passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NodePassword != b.NodePassword || string(got.Keys["swarm.key"]) != "swarm" {
+		t.Errorf("decrypted %+v, want %+v", got, b)
+	}
+	if _, err := Decrypt(data, "wrong passphrase"); !errors.Is(err, secrets.ErrWrongPassphrase) {
+		t.Errorf("decrypt with a wrong passphrase: %v, want ErrWrongPassphrase", err)
+	}
+
+	for name, bundle := range map[string]*Bundle{
+		"version": {Version: bundleVersion + 1, Keys: b.Keys},
+		"no keys": {Version: bundleVersion},
+	} {
+		data, err := bundle.Encrypt("backup passphrase")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Decrypt(data, "backup passphrase"); err == nil {
+			t.Errorf("decrypted a bundle with bad %s", name)
+		}
+	}
+}
+
+func TestRestoreKeys(t *testing.T) {
+	b := &Bundle{Version: bundleVersion, Keys: map[string][]byte{"swarm.key": []byte("swarm")}}
+	dir := t.TempDir()
+
+	if err := b.RestoreKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, keysDir, "swarm.key"))
+	if err != nil || string(data) != "swarm" {
+		t.Errorf("restored %q, %v", data, err)
+	}
+	if err := b.RestoreKeys(dir); !errors.Is(err, ErrKeystoreExists) {
+		t.Errorf("restore onto a keystore: %v, want ErrKeystoreExists", err)
+	}
+
+	for _, name := range []string{"../x.key", "sub/x.key", "/tmp/x.key", "x.txt"} {
+		dir := t.TempDir()
+		b := &Bundle{Version: bundleVersion, Keys: map[string][]byte{name: []byte("x")}}
+		if err := b.RestoreKeys(dir); err == nil {
+			t.Errorf("restored a key named %q", name)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "x.key")); err == nil {
+			t.Errorf("key %q written outside the keystore", name)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"activate/core"
+	"activate/localapi"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	return flags
+}
+
+// startNode starts the node with opts, or with the options of the last
+// start when opts is nil.
+func (c *cli) startNode(opts *core.NodeOptions) error {
+	passphrase, err := c.passphrase()
+	if err != nil {
+		return err
+	}
+	password, err := c.svc.NodePassword(passphrase)
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = core.NodeOptionsFromPreferences(c.prefs, c.dataDir)
+	}
+	if opts.SwapEnable && c.prefs.String(core.OverlayAddrPrefKey) == "" {
+		return errors.New("the node has to start in ultra-light mode first")
+	}
+	return c.svc.Start(opts, password)
+}
+
+func (c *cli) nodeInit() error {
+	passphrase, err := c.passphrase()
+	if err != nil {
+		return err
+	}
+	if err := c.svc.InitVault(passphrase); err != nil {
+		return err
+	}
+	fmt.Printf("Node set up in %s\n", c.dataDir)
+	return nil
+}
+
+func (c *cli) nodeStart(args []string) error {
+	opts := core.NodeOptionsFromPreferences(c.prefs, c.dataDir)
+	flags := newFlagSet("node start")
+	network := flags.String("network", opts.Network.Name, "network name")
+	rpc := flags.String("rpc", "", "blockchain RPC endpoint, starts the node in light mode")
+	ultraLight := flags.Bool("ultra-light", false, "start in ultra-light mode")
+	apiAddr := flags.String("api", "", "serve the local API on this loopback address, e.g. "+localapi.DefaultAddr)
+	_ = flags.Parse(args)
+
+	if opts.Network = core.FindNetworkProfile(*network); opts.Network == nil {
+		return fmt.Errorf("unknown network: %s", *network)
+	}
+	switch {
+	case *ultraLight:
+		opts.SwapEnable, opts.RPCEndpoint = false, ""
+	case *rpc != "":
+		opts.SwapEnable, opts.RPCEndpoint = true, *rpc
+	case opts.Network.UltraLightOnly:
+		opts.SwapEnable = false
+	}
+	if *apiAddr != "" {
+		if err := localapi.CheckAddr(*apiAddr); err != nil {
+			return err
+		}
+	}
+	if err := c.startNode(opts); err != nil {
+		return err
+	}
+
+	node := c.svc.Node()
+	fmt.Printf("Node running in %s mode on %s\n", node.BeeNodeMode(), opts.Network.DisplayName)
+	fmt.Printf("Address: %s\n", node.OverlayEthAddress().Hex())
+	fmt.Printf("Peers: %d\n", node.ConnectedPeerCount())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiErr := make(chan error, 1)
+	if *apiAddr != "" {
+		token, err := localapi.LoadToken(c.dataDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Local API token in %s\n", filepath.Join(c.dataDir, localapi.TokenFile))
+		go func() { apiErr <- localapi.New(c.svc, token).ListenAndServe(ctx, *apiAddr) }()
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	select {
+	case sig := <-sigs:
+		fmt.Printf("Received %s, stopping\n", sig)
+		return nil
+	case err := <-apiErr:
+		return fmt.Errorf("local API: %w", err)
+	}
+}
+
+func (c *cli) upload(args []string) error {
+	flags := newFlagSet("upload")
+	batch := flags.String("batch", "", "postage batch ID, defaults to the batch policy of the app")
+	act := flags.Bool("act", false, "protect the upload with the grantee list")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("upload needs a file")
+	}
+
+	path := flags.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	mimetype := mime.TypeByExtension(filepath.Ext(path))
+	if mimetype == "" {
+		mimetype = "application/octet-stream"
+	}
+
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	stamp, reason, err := c.svc.SelectBatch(core.BatchGroupUploads, *batch)
+	if err != nil {
+		return err
+	}
+	c.logBatch(stamp.ID(), reason)
+	ref, err := c.svc.Upload(context.Background(), core.BatchHex(stamp), filepath.Base(path), mimetype, info.Size(), *act, f)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ref.String())
+	return nil
+}
+
+func (c *cli) uploads() error {
+	uploads, err := c.svc.Uploads()
+	if err != nil {
+		return err
+	}
+	for _, u := range uploads {
+		fmt.Printf("%s  %s  %d bytes  %s\n", u.Reference, u.Timestamp.Format("2006-01-02 15:04"), u.Size, u.Name)
+	}
+	return nil
+}
+
+func (c *cli) download(args []string) error {
+	flags := newFlagSet("download")
+	out := flags.String("o", "", "output file, standard output by default")
+	publisherHex := flags.String("publisher", "", "publisher public key of access controlled content")
+	historyHex := flags.String("history", "", "history reference of access controlled content")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("download needs a reference")
+	}
+
+	ref, err := swarm.ParseHexAddress(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("reference: %w", err)
+	}
+	if (*publisherHex == "") != (*historyHex == "") {
+		return errors.New("-publisher and -history go together")
+	}
+	var publisher *ecdsa.PublicKey
+	var history *swarm.Address
+	if *publisherHex != "" {
+		if publisher, err = core.ParsePublicKey(*publisherHex); err != nil {
+			return err
+		}
+		h, err := swarm.ParseHexAddress(*historyHex)
+		if err != nil {
+			return fmt.Errorf("history reference: %w", err)
+		}
+		history = &h
+	}
+
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	r, err := c.svc.Download(context.Background(), ref, publisher, history)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, r)
+}
+
+func (c *cli) groupUpdate(op string, args []string) error {
+	flags := newFlagSet("group " + op)
+	batch := flags.String("batch", "", "postage batch ID, defaults to the batch policy of the app")
+	_ = flags.Parse(args)
+	keys := flags.Args()
+	if len(keys) == 0 {
+		return fmt.Errorf("group %s needs at least one public key", op)
+	}
+
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	stamp, reason, err := c.svc.SelectBatch(core.BatchGroupGrantees, *batch)
+	if err != nil {
+		return err
+	}
+	c.logBatch(stamp.ID(), reason)
+
+	ctx := context.Background()
+	var l *core.GranteeList
+	switch op {
+	case "create":
+		l, err = c.svc.CreateGroup(ctx, core.BatchHex(stamp), keys)
+	case "add":
+		l, err = c.svc.UpdateGroup(ctx, core.BatchHex(stamp), c.svc.HistoryRef(), keys, nil)
+	case "revoke":
+		l, err = c.svc.UpdateGroup(ctx, core.BatchHex(stamp), c.svc.HistoryRef(), nil, keys)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Grantee list: %s\nHistory: %s\n", l.Ref, l.HistoryRef)
+	return nil
+}
+
+func (c *cli) groupList() error {
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	grantees, err := c.svc.Grantees(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, g := range grantees {
+		fmt.Println(g)
+	}
+	return nil
+}
+
+func (c *cli) shareSend(args []string) error {
+	flags := newFlagSet("share send")
+	to := flags.String("to", "", "addresses of the recipients, comma separated")
+	group := flags.Bool("group", false, "send to every grantee of the group")
+	speedName := flags.String("speed", "", "fee speed: slow, normal or fast")
+	_ = flags.Parse(args)
+	speed, err := core.ParseSpeed(*speedName)
+	if err != nil {
+		return err
+	}
+	if (*to == "") == !*group {
+		return errors.New("share send needs either -to or -group")
+	}
+	var targets []common.Address
+	for _, addr := range strings.Split(*to, ",") {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid -to address: %s", addr)
+		}
+		targets = append(targets, common.HexToAddress(addr))
+	}
+	if flags.NArg() != 1 {
+		return errors.New("share send needs a reference")
+	}
+	ref, err := swarm.ParseHexAddress(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("reference: %w", err)
+	}
+
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	ctx := core.WithSpeed(context.Background(), speed)
+	if *group {
+		if targets, err = c.svc.GroupAddresses(ctx); err != nil {
+			return err
+		}
+	}
+	fee, err := c.svc.EstimateShares(ctx, targets, ref)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Estimated cost %s xDAI (at most %s) at %s speed, balance %s xDAI\n",
+		formatWei(fee.Expected()), formatWei(fee.Max()), fee.Speed, formatWei(fee.Balance))
+	if !fee.Affordable() {
+		return fmt.Errorf("the balance does not cover the %s xDAI the transaction needs upfront", formatWei(fee.Upfront()))
+	}
+	receipts, err := c.svc.SendShares(ctx, targets, ref)
+	for _, receipt := range receipts {
+		fmt.Printf("Sent in tx %s, block %d\n", receipt.TxHash.Hex(), receipt.BlockNumber.Uint64())
+	}
+	return err
+}
+
+func (c *cli) outbox() error {
+	entries, err := c.svc.Outbox()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		fmt.Printf("%s  %-9s  to %s  tx %s", e.Timestamp.Format("2006-01-02 15:04"), e.Status, strings.Join(e.Recipients(), ","), e.TxHash)
+		if e.BlockNumber != 0 {
+			fmt.Printf("  block %d  gas %d", e.BlockNumber, e.GasUsed)
+		}
+		if e.MinedTx != "" {
+			fmt.Printf("  mined as %s", e.MinedTx)
+		}
+		if e.ResentAs != "" {
+			fmt.Printf("  sent again as %s", e.ResentAs)
+		}
+		if e.Reference != "" {
+			fmt.Printf("  %s", e.Reference)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// outboxReplace speeds up or cancels a pending share and waits until one of
+// its transactions is mined.
+func (c *cli) outboxReplace(sub string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("outbox %s needs a transaction hash", sub)
+	}
+	txHash := common.HexToHash(args[0])
+	if _, err := c.svc.OutboxEntry(txHash); err != nil {
+		return err
+	}
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	replace := c.svc.SpeedUp
+	if sub == "cancel" {
+		replace = c.svc.Cancel
+	}
+	replacement, err := replace(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Sent %s, waiting for the share to be mined\n", replacement.Hex())
+	if err := c.svc.RecheckOutbox(ctx); err != nil {
+		return err
+	}
+	return c.outbox()
+}
+
+func (c *cli) outboxRecheck() error {
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	// an interrupted recheck leaves the entries pending for the next one
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := c.svc.RecheckOutbox(ctx); err != nil {
+		return err
+	}
+	return c.outbox()
+}
+
+func (c *cli) inboxList() error {
+	shares, err := c.svc.Inbox(context.Background())
+	if err != nil {
+		return err
+	}
+	for n, s := range shares {
+		fmt.Printf("%d  block %d  from %s  %s\n", n+1, s.BlockNumber, s.From.Hex(), s.Reference)
+	}
+	return nil
+}
+
+func (c *cli) inboxFetch(args []string) error {
+	flags := newFlagSet("inbox fetch")
+	out := flags.String("o", "", "output file, standard output by default")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("inbox fetch needs the number of a share, see inbox list")
+	}
+	n, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("share number: %w", err)
+	}
+
+	if err := c.startNode(nil); err != nil {
+		return err
+	}
+	shares, err := c.svc.Inbox(context.Background())
+	if err != nil {
+		return err
+	}
+	if n < 1 || n > len(shares) {
+		return fmt.Errorf("no share %d, the inbox has %d", n, len(shares))
+	}
+	r, err := c.svc.Fetch(context.Background(), shares[n-1])
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, r)
+}
+
+func (c *cli) logBatch(id []byte, reason string) {
+	fmt.Fprintf(os.Stderr, "Using batch %x (%s)\n", id, reason)
+}
+
+// formatWei formats wei as xDAI with the precision of transaction fees.
+func formatWei(wei *big.Int) string {
+	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Text('f', 8)
+}
+
+func writeOutput(path string, r io.Reader) error {
+	if path == "" {
+		_, err := io.Copy(os.Stdout, r)
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,169 @@
+// Command activate runs an ACTivate node without the GUI. It uses the data
+// dir and the preferences of the app, so the node, its batches, grantee list
+// and uploads are the same in both.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"activate/core"
+)
+
+const (
+	passphraseEnv = "ACTIVATE_PASSPHRASE"
+	dataDirEnv    = "ACTIVATE_DIR"
+)
+
+const usage = `usage: activate [-dir path] [-passphrase-file path] <command> [arguments]
+
+commands:
+  node init                     set up the vault of a new node
+  node start [-rpc url] [-api address]
+                                run the node until interrupted, -rpc starts it in light mode,
+                                -api serves the local API on a loopback address
+  upload [-batch id] [-act] file
+  uploads                       list the uploaded files
+  download [-o file] [-publisher key -history ref] ref
+  group create [-batch id] key...
+  group add [-batch id] key...
+  group revoke [-batch id] key...
+  group list
+  share send [-speed slow|normal|fast] -to address[,address...] ref
+  share send [-speed slow|normal|fast] -group ref
+                                send to every grantee in one transaction
+  outbox                        list the sent shares
+  outbox recheck                wait for the receipts of pending shares
+  outbox speedup tx             cancel a pending share and send it again faster
+  outbox cancel tx              replace a pending share by an empty transaction
+  inbox list
+  inbox fetch [-o file] n
+
+The vault passphrase is read from $ACTIVATE_PASSPHRASE, the passphrase file
+or standard input, in that order. Commands other than "inbox list" and
+"outbox" start the node and stop it when done, the app must not run on the
+same data dir.
+`
+
+type cli struct {
+	dataDir        string
+	passphraseFile string
+	prefs          *core.FilePreferences
+	svc            *core.Service
+}
+
+func main() {
+	c := &cli{}
+	flags := flag.NewFlagSet("activate", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.StringVar(&c.dataDir, "dir", os.Getenv(dataDirEnv), "app data dir, defaults to the one of the GUI")
+	flags.StringVar(&c.passphraseFile, "passphrase-file", "", "file holding the vault passphrase")
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err := c.run(flags.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "activate: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(args []string) error {
+	if c.dataDir == "" {
+		dir, err := core.DefaultAppDir()
+		if err != nil {
+			return fmt.Errorf("app data dir: %w", err)
+		}
+		c.dataDir = dir
+	}
+	prefs, err := core.OpenFilePreferences(c.dataDir)
+	if err != nil {
+		return err
+	}
+	c.prefs = prefs
+	c.svc = core.NewService(c.dataDir, prefs, &logger{})
+	defer func() {
+		// a node that cannot stop in-process stops with the command
+		if err := c.svc.Stop(); err != nil && !errors.Is(err, core.ErrRestartRequired) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}()
+
+	cmd, args := args[0], args[1:]
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	switch {
+	case cmd == "node" && sub == "init":
+		err = c.nodeInit()
+	case cmd == "node" && sub == "start":
+		err = c.nodeStart(args[1:])
+	case cmd == "upload":
+		err = c.upload(args)
+	case cmd == "uploads":
+		err = c.uploads()
+	case cmd == "download":
+		err = c.download(args)
+	case cmd == "group" && (sub == "create" || sub == "add" || sub == "revoke"):
+		err = c.groupUpdate(sub, args[1:])
+	case cmd == "group" && sub == "list":
+		err = c.groupList()
+	case cmd == "share" && sub == "send":
+		err = c.shareSend(args[1:])
+	case cmd == "outbox" && sub == "":
+		err = c.outbox()
+	case cmd == "outbox" && sub == "recheck":
+		err = c.outboxRecheck()
+	case cmd == "outbox" && (sub == "speedup" || sub == "cancel"):
+		err = c.outboxReplace(sub, args[1:])
+	case cmd == "inbox" && sub == "list":
+		err = c.inboxList()
+	case cmd == "inbox" && sub == "fetch":
+		err = c.inboxFetch(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command: %s", strings.TrimSpace(cmd+" "+sub))
+	}
+	if err != nil {
+		return err
+	}
+	return c.prefs.Err()
+}
+
+// passphrase reads the vault passphrase. A prompt on standard input echoes
+// the passphrase, prefer the environment or a file in scripts.
+func (c *cli) passphrase() (string, error) {
+	if p := os.Getenv(passphraseEnv); p != "" {
+		return p, nil
+	}
+	if c.passphraseFile != "" {
+		data, err := os.ReadFile(c.passphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("passphrase file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	fmt.Fprint(os.Stderr, "Vault passphrase: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("passphrase cannot be blank")
+	}
+	return line, nil
+}
+
+type logger struct{}
+
+func (*logger) Log(s string) {
+	fmt.Fprintln(os.Stderr, s)
+}
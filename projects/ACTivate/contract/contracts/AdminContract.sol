@@ -42,4 +42,26 @@ contract DataContract {
         // Emit event with the data (from = msg.sender, the actual caller)
         emit DataSentToTarget(msg.sender, target, ownerParam, actref, topic);
     }
+
+    /**
+     * @dev Public function to emit the same data to several target addresses
+     * in one transaction, one DataSentToTarget event per target
+     * @param targets The target addresses
+     * @param ownerParam First 64-byte (32-byte) data parameter representing owner
+     * @param actref Second 64-byte (32-byte) data parameter representing action reference
+     * @param topic String parameter for the topic
+     */
+    function sendDataToTargets(
+        address[] calldata targets,
+        bytes32 ownerParam,
+        bytes32 actref,
+        string calldata topic
+    ) external {
+        require(targets.length > 0, "DataContract: no targets");
+
+        for (uint256 i = 0; i < targets.length; i++) {
+            require(targets[i] != address(0), "DataContract: target cannot be zero address");
+            emit DataSentToTarget(msg.sender, targets[i], ownerParam, actref, topic);
+        }
+    }
 }
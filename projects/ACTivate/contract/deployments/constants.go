@@ -1,4 +1,4 @@
-package main
+package deployments
 
 // AdminContract Sepolia deployment constants
 // Generated on 2025-06-14T11:37:49.207Z
@@ -1,7 +1,7 @@
 // Code generated by Gnosis deployment script. DO NOT EDIT.
 // Generated on 2025-06-14T13:09:38.610Z
 
-package main
+package deployments
 
 const (
 	// Gnosis Chain Configuration
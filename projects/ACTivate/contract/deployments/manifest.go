@@ -0,0 +1,173 @@
+package deployments
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// ManifestPattern matches the deployment files written by the deploy
+	// scripts.
+	ManifestPattern = "*-deployment.json"
+	// DataContractName is the contract name of data contract manifests,
+	// manifests without a name are data contract deployments too.
+	DataContractName = "DataContract"
+)
+
+//go:embed *-deployment.json datacontract-abi.json
+var embedded embed.FS
+
+// Manifest describes a single DataContract deployment.
+type Manifest struct {
+	Network         string          `json:"network"`
+	ChainID         int64           `json:"chainId"`
+	ContractName    string          `json:"contractName,omitempty"`
+	ContractAddress string          `json:"contractAddress"`
+	ABI             json.RawMessage `json:"abi,omitempty"`
+	DeploymentBlock uint64          `json:"blockNumber"`
+	RPCUrl          string          `json:"rpcUrl,omitempty"`
+	TransactionHash string          `json:"transactionHash,omitempty"`
+	Source          string          `json:"-"`
+}
+
+// UnmarshalJSON accepts both the "abi" key and the "contractABI" key used by
+// the Sepolia deploy script.
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	type manifest Manifest
+	aux := struct {
+		*manifest
+		ContractABI json.RawMessage `json:"contractABI,omitempty"`
+	}{manifest: (*manifest)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(m.ABI) == 0 {
+		m.ABI = aux.ContractABI
+	}
+	return nil
+}
+
+// Validate checks the fields needed to talk to the deployed contract.
+func (m *Manifest) Validate() error {
+	if m.Network == "" {
+		return errors.New("network is missing")
+	}
+	if m.ChainID <= 0 {
+		return fmt.Errorf("invalid chain ID %d", m.ChainID)
+	}
+	if !common.IsHexAddress(m.ContractAddress) {
+		return fmt.Errorf("invalid contract address %q", m.ContractAddress)
+	}
+	return nil
+}
+
+// Address returns the contract address of the deployment.
+func (m *Manifest) Address() common.Address {
+	return common.HexToAddress(m.ContractAddress)
+}
+
+// ParsedABI returns the ABI of the deployment, falling back to the bundled
+// DataContract ABI when the manifest does not carry one.
+func (m *Manifest) ParsedABI() (abi.ABI, error) {
+	if len(bytes.TrimSpace(m.ABI)) == 0 {
+		return DefaultABI()
+	}
+	return abi.JSON(bytes.NewReader(m.ABI))
+}
+
+// DefaultABI returns the bundled DataContract ABI.
+func DefaultABI() (abi.ABI, error) {
+	data, err := embedded.ReadFile("datacontract-abi.json")
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	return abi.JSON(bytes.NewReader(data))
+}
+
+// Parse decodes and validates a deployment manifest.
+func Parse(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("decode deployment manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid deployment manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Load reads a deployment manifest from a file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Source = path
+	return m, nil
+}
+
+// LoadDir reads every deployment manifest in dir. A missing directory is not
+// an error.
+func LoadDir(dir string) ([]*Manifest, error) {
+	return loadFS(os.DirFS(dir), dir)
+}
+
+// Embedded returns the manifests bundled with the binary.
+func Embedded() ([]*Manifest, error) {
+	return loadFS(embedded, "embedded")
+}
+
+func loadFS(fsys fs.FS, name string) ([]*Manifest, error) {
+	paths, err := fs.Glob(fsys, ManifestPattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	manifests := make([]*Manifest, 0, len(paths))
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		m, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(name, p), err)
+		}
+		m.Source = filepath.Join(name, p)
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// Latest returns the most recent data contract deployment on the given
+// chain, or nil. Deployments of other contracts are skipped.
+func Latest(manifests []*Manifest, chainID int64) *Manifest {
+	var latest *Manifest
+	for _, m := range manifests {
+		if m.ChainID != chainID || (m.ContractName != "" && m.ContractName != DataContractName) {
+			continue
+		}
+		if latest == nil || m.DeploymentBlock >= latest.DeploymentBlock {
+			latest = m
+		}
+	}
+	return latest
+}
@@ -8,6 +8,9 @@ import (
 	"math/big"
 	"os"
 
+	"activate/contract/deployments"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -37,10 +40,25 @@ func main() {
 		}
 	}
 
+	// Pick the latest Gnosis deployment from the deployment manifests
+	manifests, err := deployments.LoadDir("deployments")
+	if err != nil {
+		log.Fatal("Failed to load deployment manifests:", err)
+	}
+	var deployment *deployments.Manifest
+	if m := deployments.Latest(manifests, GnosisChainID); m != nil {
+		deployment = m
+	} else if m := deployments.Latest(manifests, ChiadoChainID); m != nil {
+		deployment = m
+	}
+
 	// Configuration
 	rpcURL := getEnvOrDefault("GNOSIS_RPC_URL", GnosisRPCURL)
 	contractAddressStr := os.Getenv("GNOSIS_CONTRACT_ADDRESS")
 	privateKeyStr := os.Getenv("PRIVATE_KEY")
+	if contractAddressStr == "" && deployment != nil {
+		contractAddressStr = deployment.ContractAddress
+	}
 
 	if contractAddressStr == "" {
 		log.Fatal("GNOSIS_CONTRACT_ADDRESS not set. Please deploy the contract first with: npm run deploy:gnosis")
@@ -103,34 +121,17 @@ func main() {
 	// Parse contract address
 	contractAddress := common.HexToAddress(contractAddressStr)
 
-	// Create contract instance (simplified ABI for demonstration)
-	// In a real application, you would generate Go bindings from the ABI
-	contractABI := `[
-		{
-			"inputs": [
-				{"internalType": "address", "name": "target", "type": "address"},
-				{"internalType": "bytes32", "name": "ownerParam", "type": "bytes32"},
-				{"internalType": "bytes32", "name": "actref", "type": "bytes32"},
-				{"internalType": "string", "name": "topic", "type": "string"}
-			],
-			"name": "sendDataToTarget",
-			"outputs": [],
-			"stateMutability": "nonpayable",
-			"type": "function"
-		},
-		{
-			"anonymous": false,
-			"inputs": [
-				{"indexed": true, "internalType": "address", "name": "from", "type": "address"},
-				{"indexed": true, "internalType": "address", "name": "to", "type": "address"},
-				{"indexed": false, "internalType": "bytes32", "name": "owner", "type": "bytes32"},
-				{"indexed": false, "internalType": "bytes32", "name": "actref", "type": "bytes32"},
-				{"indexed": false, "internalType": "string", "name": "topic", "type": "string"}
-			],
-			"name": "DataSentToTarget",
-			"type": "event"
-		}
-	]`
+	// Load the contract ABI from the deployment manifest
+	var contractABI abi.ABI
+	if deployment != nil {
+		contractABI, err = deployment.ParsedABI()
+	} else {
+		contractABI, err = deployments.DefaultABI()
+	}
+	if err != nil {
+		log.Fatal("Failed to parse contract ABI:", err)
+	}
+	fmt.Printf("Contract ABI methods: %d\n", len(contractABI.Methods))
 
 	// Get the current gas price
 	gasPrice, err := client.SuggestGasPrice(context.Background())
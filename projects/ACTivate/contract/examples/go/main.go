@@ -9,8 +9,9 @@ import (
 	"os"
 	"strings"
 
+	"activate/contract/deployments"
+
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -18,105 +19,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// DataContract ABI - Generated from compiled contract
-const DataContractABI = `[
-	{
-		"inputs": [],
-		"stateMutability": "nonpayable",
-		"type": "constructor"
-	},
-	{
-		"inputs": [
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "from",
-				"type": "address"
-			},
-			{
-				"indexed": true,
-				"internalType": "address",
-				"name": "to",
-				"type": "address"
-			},
-			{
-				"indexed": false,
-				"internalType": "bytes32",
-				"name": "owner",
-				"type": "bytes32"
-			},
-			{
-				"indexed": false,
-				"internalType": "bytes32",
-				"name": "actref",
-				"type": "bytes32"
-			},
-			{
-				"indexed": false,
-				"internalType": "string",
-				"name": "topic",
-				"type": "string"
-			}
-		],
-		"name": "DataSentToTarget",
-		"type": "event"
-	},
-	{
-		"inputs": [],
-		"name": "getAdmin",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [],
-		"name": "owner",
-		"outputs": [
-			{
-				"internalType": "address",
-				"name": "",
-				"type": "address"
-			}
-		],
-		"stateMutability": "view",
-		"type": "function"
-	},
-	{
-		"inputs": [
-			{
-				"internalType": "address",
-				"name": "target",
-				"type": "address"
-			},
-			{
-				"internalType": "bytes32",
-				"name": "ownerParam",
-				"type": "bytes32"
-			},
-			{
-				"internalType": "bytes32",
-				"name": "actref",
-				"type": "bytes32"
-			},
-			{
-				"internalType": "string",
-				"name": "topic",
-				"type": "string"
-			}
-		],
-		"name": "sendDataToTarget",
-		"outputs": [],
-		"stateMutability": "nonpayable",
-		"type": "function"
-	}
-]`
-
 // DataSentToTargetEvent represents the event emitted by the contract
 type DataSentToTargetEvent struct {
 	From   common.Address
@@ -129,9 +31,17 @@ type DataSentToTargetEvent struct {
 func main() {
 	fmt.Println("=== AdminContract Go Interaction Example ===\n")
 
+	// Load the deployment manifest written by the deploy scripts
+	deploymentFile := getEnv("DEPLOYMENT_FILE", "../../deployments/sepolia-deployment.json")
+	deployment, err := deployments.Load(deploymentFile)
+	if err != nil {
+		log.Fatalf("Failed to load deployment manifest: %v", err)
+	}
+	fmt.Printf("Deployment: %s (chain ID %d) from %s\n", deployment.Network, deployment.ChainID, deploymentFile)
+
 	// Get environment variables
-	rpcURL := getEnv("RPC_URL", "http://localhost:8545")
-	contractAddressHex := getEnv("CONTRACT_ADDRESS", "")
+	rpcURL := getEnv("RPC_URL", deployment.RPCUrl)
+	contractAddressHex := getEnv("CONTRACT_ADDRESS", deployment.ContractAddress)
 	privateKeyHex := getEnv("PRIVATE_KEY", "")
 
 	if contractAddressHex == "" {
@@ -170,7 +80,7 @@ func main() {
 	fmt.Printf("Contract address: %s\n", contractAddress.Hex())
 
 	// Parse ABI
-	parsedABI, err := abi.JSON(strings.NewReader(DataContractABI))
+	parsedABI, err := deployment.ParsedABI()
 	if err != nil {
 		log.Fatalf("Failed to parse ABI: %v", err)
 	}
@@ -341,8 +251,9 @@ func main() {
 	fmt.Println("Successfully demonstrated AdminContract interaction from Go!")
 	fmt.Println("\nTo run this example:")
 	fmt.Println("1. Set environment variables:")
-	fmt.Println("   export RPC_URL=http://localhost:8545")
-	fmt.Println("   export CONTRACT_ADDRESS=0x...")
+	fmt.Println("   export DEPLOYMENT_FILE=../../deployments/sepolia-deployment.json")
+	fmt.Println("   export RPC_URL=http://localhost:8545 (optional, overrides the manifest)")
+	fmt.Println("   export CONTRACT_ADDRESS=0x... (optional, overrides the manifest)")
 	fmt.Println("   export PRIVATE_KEY=0x...")
 	fmt.Println("2. Install dependencies: go mod init && go mod tidy")
 	fmt.Println("3. Run: go run main.go")
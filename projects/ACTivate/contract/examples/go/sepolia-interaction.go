@@ -9,8 +9,9 @@ import (
 	"os"
 	"strings"
 
+	"activate/contract/deployments"
+
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -18,32 +19,39 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// LoadDeploymentConfig loads the deployment configuration from environment or constants
-func LoadDeploymentConfig() (string, string, string) {
-	// Try to load from environment first
+// sepoliaDeploymentFile is the manifest written by scripts/deployToSepolia.ts
+const sepoliaDeploymentFile = "../../deployments/sepolia-deployment.json"
+
+// LoadDeploymentConfig loads the deployment configuration from environment or the deployment manifest
+func LoadDeploymentConfig() (*deployments.Manifest, string, string, string) {
+	deployment, err := deployments.Load(sepoliaDeploymentFile)
+	if err != nil {
+		log.Fatalf("Failed to load deployment manifest: %v", err)
+	}
+
+	// Environment variables override the manifest
 	rpcURL := os.Getenv("RPC_URL")
 	contractAddress := os.Getenv("CONTRACT_ADDRESS")
 	privateKey := os.Getenv("PRIVATE_KEY")
 
-	// Fallback to constants (replace these with your actual deployment values)
 	if rpcURL == "" {
-		rpcURL = "https://sepolia.infura.io/v3/YOUR_PROJECT_ID" // Replace with actual RPC URL
+		rpcURL = deployment.RPCUrl
 	}
 	if contractAddress == "" {
-		contractAddress = "0x0000000000000000000000000000000000000000" // Replace with actual contract address
+		contractAddress = deployment.ContractAddress
 	}
 	if privateKey == "" {
 		privateKey = "0x0000000000000000000000000000000000000000000000000000000000000000" // Replace with actual private key
 	}
 
-	return rpcURL, contractAddress, privateKey
+	return deployment, rpcURL, contractAddress, privateKey
 }
 
 func main() {
 	fmt.Println("=== AdminContract Sepolia Interaction ===\n")
 
 	// Load deployment configuration
-	rpcURL, contractAddressHex, privateKeyHex := LoadDeploymentConfig()
+	deployment, rpcURL, contractAddressHex, privateKeyHex := LoadDeploymentConfig()
 
 	if contractAddressHex == "0x0000000000000000000000000000000000000000" {
 		log.Fatal(`❌ Contract address not set!
@@ -54,8 +62,7 @@ Please either:
    export CONTRACT_ADDRESS="0x..."
    export PRIVATE_KEY="0x..."
 
-2. Or update the constants in LoadDeploymentConfig() function with your deployment values.
-   You can find these values in the deployments/constants.go file after deployment.`)
+2. Or deploy with npm run deploy:sepolia, which writes deployments/sepolia-deployment.json.`)
 	}
 
 	fmt.Printf("🔗 RPC URL: %s\n", rpcURL)
@@ -74,8 +81,8 @@ Please either:
 		log.Fatalf("Failed to get network ID: %v", err)
 	}
 
-	if chainID.Int64() != 11155111 {
-		log.Printf("⚠️  Warning: Connected to chain ID %d, expected Sepolia (11155111)", chainID.Int64())
+	if chainID.Int64() != deployment.ChainID {
+		log.Printf("⚠️  Warning: Connected to chain ID %d, expected %s (%d)", chainID.Int64(), deployment.Network, deployment.ChainID)
 	} else {
 		fmt.Println("✅ Connected to Sepolia testnet")
 	}
@@ -106,108 +113,8 @@ Please either:
 	// Parse contract address
 	contractAddress := common.HexToAddress(contractAddressHex)
 
-	// AdminContract ABI - This should match your deployed contract
-	const DataContractABI = `[
-		{
-			"inputs": [],
-			"stateMutability": "nonpayable",
-			"type": "constructor"
-		},
-		{
-			"anonymous": false,
-			"inputs": [
-				{
-					"indexed": true,
-					"internalType": "address",
-					"name": "from",
-					"type": "address"
-				},
-				{
-					"indexed": true,
-					"internalType": "address",
-					"name": "to",
-					"type": "address"
-				},
-				{
-					"indexed": false,
-					"internalType": "bytes32",
-					"name": "owner",
-					"type": "bytes32"
-				},
-				{
-					"indexed": false,
-					"internalType": "bytes32",
-					"name": "actref",
-					"type": "bytes32"
-				},
-				{
-					"indexed": false,
-					"internalType": "string",
-					"name": "topic",
-					"type": "string"
-				}
-			],
-			"name": "DataSentToTarget",
-			"type": "event"
-		},
-		{
-			"inputs": [],
-			"name": "getAdmin",
-			"outputs": [
-				{
-					"internalType": "address",
-					"name": "",
-					"type": "address"
-				}
-			],
-			"stateMutability": "view",
-			"type": "function"
-		},
-		{
-			"inputs": [],
-			"name": "owner",
-			"outputs": [
-				{
-					"internalType": "address",
-					"name": "",
-					"type": "address"
-				}
-			],
-			"stateMutability": "view",
-			"type": "function"
-		},
-		{
-			"inputs": [
-				{
-					"internalType": "address",
-					"name": "target",
-					"type": "address"
-				},
-				{
-					"internalType": "bytes32",
-					"name": "ownerParam",
-					"type": "bytes32"
-				},
-				{
-					"internalType": "bytes32",
-					"name": "actref",
-					"type": "bytes32"
-				},
-				{
-					"internalType": "string",
-					"name": "topic",
-					"type": "string"
-				}
-			],
-			"name": "sendDataToTarget",
-			"outputs": [],
-			"stateMutability": "nonpayable",
-			"type": "function"
-		}
-	]`
-
 	// Parse ABI
-	parsedABI, err := abi.JSON(strings.NewReader(DataContractABI))
+	parsedABI, err := deployment.ParsedABI()
 	if err != nil {
 		log.Fatalf("Failed to parse ABI: %v", err)
 	}
@@ -63,6 +63,7 @@ async function main() {
     deployerAddress: deployer.address,
     transactionHash: deploymentTx?.hash,
     blockNumber: deploymentTx?.blockNumber,
+    abi: JSON.parse(DataContract.interface.formatJson()),
     gasUsed: deploymentTx?.gasLimit?.toString(),
     timestamp: new Date().toISOString(),
     rpcUrl: process.env.CHIADO_RPC_URL || "https://rpc.chiadochain.net"
@@ -100,7 +101,7 @@ CHIADO_BLOCK_EXPLORER=https://gnosis-chiado.blockscout.com
   const goContent = `// Code generated by Chiado deployment script. DO NOT EDIT.
 // Generated on ${new Date().toISOString()}
 
-package main
+package deployments
 
 const (
 	// Chiado Testnet Configuration
@@ -64,6 +64,7 @@ async function main() {
     deployerAddress: deployer.address,
     transactionHash: deploymentTx?.hash,
     blockNumber: deploymentTx?.blockNumber,
+    abi: JSON.parse(DataContract.interface.formatJson()),
     gasUsed: deploymentTx?.gasLimit?.toString(),
     timestamp: new Date().toISOString(),
     rpcUrl: process.env.GNOSIS_RPC_URL || "https://rpc.gnosischain.com"
@@ -101,7 +102,7 @@ GNOSIS_BLOCK_EXPLORER=https://gnosisscan.io
   const goContent = `// Code generated by Gnosis deployment script. DO NOT EDIT.
 // Generated on ${new Date().toISOString()}
 
-package main
+package deployments
 
 const (
 	// Gnosis Chain Configuration
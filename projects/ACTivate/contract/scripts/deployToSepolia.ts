@@ -172,7 +172,7 @@ async function main(): Promise<void> {
 }
 
 function generateGoConfig(config: DeploymentConfig): string {
-  return `package main
+  return `package deployments
 
 import (
 	"encoding/json"
@@ -239,7 +239,7 @@ ETHERSCAN_TX_URL=https://sepolia.etherscan.io/tx/${config.transactionHash}
 }
 
 function generateGoConstants(config: DeploymentConfig): string {
-  return `package main
+  return `package deployments
 
 // DataContract Sepolia deployment constants
 // Generated on ${config.deployedAt}
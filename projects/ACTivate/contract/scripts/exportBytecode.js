@@ -0,0 +1,16 @@
+// Writes the creation bytecode of DataContract from the hardhat artifact to
+// deployments/datacontract-bytecode.hex, next to the ABI. The Go tests deploy
+// it on their simulated chain. Run `npm run compile` first.
+const fs = require('fs');
+const path = require('path');
+
+const artifactPath = path.join(__dirname, '..', 'artifacts', 'contracts', 'AdminContract.sol', 'DataContract.json');
+const outPath = path.join(__dirname, '..', 'deployments', 'datacontract-bytecode.hex');
+
+const artifact = JSON.parse(fs.readFileSync(artifactPath, 'utf8'));
+if (!artifact.bytecode || artifact.bytecode === '0x') {
+  console.error(`No bytecode in ${artifactPath}`);
+  process.exit(1);
+}
+fs.writeFileSync(outPath, artifact.bytecode + '\n');
+console.log(`Wrote ${outPath}`);
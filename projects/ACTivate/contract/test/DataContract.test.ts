@@ -111,6 +111,43 @@ describe("DataContract", function () {
     });
   });
 
+  describe("sendDataToTargets", function () {
+    it("Should emit one event per target in a single transaction", async function () {
+      const ownerParam = ethers.encodeBytes32String("OWNER_001");
+      const actref = ethers.encodeBytes32String("ACTION_REF_123");
+      const topic = "Group Topic";
+      const targets = [targetAddress.address, user1.address, user2.address];
+
+      const tx = await dataContract.sendDataToTargets(targets, ownerParam, actref, topic);
+      const receipt = await tx.wait();
+      expect(receipt?.logs).to.have.length(targets.length);
+
+      for (const target of targets) {
+        await expect(tx)
+          .to.emit(dataContract, "DataSentToTarget")
+          .withArgs(owner.address, target, ownerParam, actref, topic);
+      }
+    });
+
+    it("Should revert without targets", async function () {
+      const ownerParam = ethers.encodeBytes32String("OWNER_001");
+      const actref = ethers.encodeBytes32String("ACTION_REF_123");
+
+      await expect(
+        dataContract.sendDataToTargets([], ownerParam, actref, "Group Topic")
+      ).to.be.revertedWith("DataContract: no targets");
+    });
+
+    it("Should revert if any target is zero address", async function () {
+      const ownerParam = ethers.encodeBytes32String("OWNER_001");
+      const actref = ethers.encodeBytes32String("ACTION_REF_123");
+
+      await expect(
+        dataContract.sendDataToTargets([user1.address, ethers.ZeroAddress], ownerParam, actref, "Group Topic")
+      ).to.be.revertedWith("DataContract: target cannot be zero address");
+    });
+  });
+
   describe("Multi-User Access", function () {
     it("Should allow multiple users to call the function simultaneously", async function () {
       const ownerParam = ethers.encodeBytes32String("MULTI_USER");
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/v2/pkg/postage"
+)
+
+const (
+	// BatchPolicySelected always uses the selected batch.
+	BatchPolicySelected = "Selected batch"
+	// BatchPolicyGroup uses the default batch of the operation group and falls
+	// back to the selected batch.
+	BatchPolicyGroup = "Group default"
+	// BatchPolicyAuto uses the batch with the most remaining capacity.
+	BatchPolicyAuto = "Most remaining capacity"
+
+	BatchGroupUploads  = "uploads"
+	BatchGroupGrantees = "grantees"
+)
+
+var BatchPolicies = []string{BatchPolicySelected, BatchPolicyGroup, BatchPolicyAuto}
+
+var (
+	ErrNoUsableBatch   = errors.New("no usable postage batch")
+	ErrNoBatchSelected = errors.New("no postage batch selected")
+)
+
+// NormalizeBatchPolicy returns policy if it is known, the selected batch
+// policy otherwise.
+func NormalizeBatchPolicy(policy string) string {
+	for _, p := range BatchPolicies {
+		if p == policy {
+			return p
+		}
+	}
+	return BatchPolicySelected
+}
+
+// SelectBatch picks the batch for an operation of group out of the usable
+// stamps and returns it with the reason it was chosen. groupBatch and
+// selected are batch IDs in hex, empty when not set.
+func SelectBatch(stamps []*postage.StampIssuer, policy, group, groupBatch, selected string) (*postage.StampIssuer, string, error) {
+	if len(stamps) == 0 {
+		return nil, "", ErrNoUsableBatch
+	}
+
+	switch NormalizeBatchPolicy(policy) {
+	case BatchPolicyAuto:
+		return MostRemainingBatch(stamps), "most remaining capacity", nil
+	case BatchPolicyGroup:
+		if stamp := FindBatch(stamps, groupBatch); stamp != nil {
+			return stamp, fmt.Sprintf("default for %s", group), nil
+		}
+	}
+	if stamp := FindBatch(stamps, selected); stamp != nil {
+		return stamp, "selected", nil
+	}
+	return nil, "", ErrNoBatchSelected
+}
+
+// FindBatch returns the stamp with the hex batch ID or nil.
+func FindBatch(stamps []*postage.StampIssuer, batchHex string) *postage.StampIssuer {
+	if batchHex == "" {
+		return nil
+	}
+	for _, stamp := range stamps {
+		if hex.EncodeToString(stamp.ID()) == batchHex {
+			return stamp
+		}
+	}
+	return nil
+}
+
+// MostRemainingBatch returns the batch that can take the most chunks before
+// its fullest bucket is full.
+func MostRemainingBatch(stamps []*postage.StampIssuer) *postage.StampIssuer {
+	var best *postage.StampIssuer
+	var bestRemaining uint64
+	for _, stamp := range stamps {
+		remaining := uint64(stamp.BucketUpperBound()-stamp.Utilization()) << stamp.BucketDepth()
+		if best == nil || remaining > bestRemaining {
+			best, bestRemaining = stamp, remaining
+		}
+	}
+	return best
+}
@@ -0,0 +1,375 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"activate/contract/deployments"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+var (
+	ErrNoDataContract = errors.New("no data contract on this network")
+	ErrNoHistory      = errors.New("no history reference, upload with access control first")
+	ErrNoShare        = errors.New("no share received yet")
+	ErrNoTargets      = errors.New("no targets to send to")
+)
+
+// chainConn is a snapshot of the data contract connection of a Service.
+type chainConn struct {
+	contract DataContractInterface
+	client   *ethclient.Client
+	abi      abi.ABI
+	trackCtx context.Context
+}
+
+// ConnectContract connects to the data contract through the first healthy
+// contract RPC. Before the node is started the contract can only be read,
+// with the options saved by the last start.
+func (s *Service) ConnectContract(ctx context.Context) error {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	return s.connect(ctx)
+}
+
+// connect is ConnectContract, the caller must hold connectMu. The RPCs are
+// dialed without holding mu, the new connection replaces the previous one
+// once it is up.
+func (s *Service) connect(ctx context.Context) error {
+	s.mu.RLock()
+	node, opts := s.bl, s.opts
+	s.mu.RUnlock()
+	var txService transaction.Service
+	if node != nil {
+		txService = node.TransactionService()
+	} else {
+		opts = NodeOptionsFromPreferences(s.Prefs, s.DataDir)
+	}
+	profile := opts.Network
+	if !profile.HasDataContract() {
+		return ErrNoDataContract
+	}
+	contractABI, err := profile.Deployment.ParsedABI()
+	if err != nil {
+		return fmt.Errorf("data contract ABI: %w", err)
+	}
+	owner, err := s.address(node)
+	if err != nil {
+		return err
+	}
+
+	pool := NewRPCPool(profile.ChainID, opts.ContractRPCEndpoints()...)
+	client, err := pool.Connect(ctx)
+	if err != nil {
+		pool.Close()
+		s.Disconnect()
+		return fmt.Errorf("contract rpc: %w", err)
+	}
+	s.Logger.Log(fmt.Sprintf("Contract RPC connected via %s", RedactRPCEndpoint(pool.Active())))
+	contract := NewDataContract(owner, profile.Deployment.Address(), contractABI, txService, true, s.deploymentBlock(ctx, client, profile.Deployment))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnect()
+	s.rpcPool = pool
+	s.ethClient = client
+	s.trackCtx, s.stopTracking = context.WithCancel(context.Background())
+	s.contractABI = contractABI
+	s.contract = contract
+	return nil
+}
+
+// deploymentBlock returns the block the data contract was deployed in. A
+// manifest without it gets it from the receipt of its deployment transaction
+// once, otherwise every backfill would scan the chain from genesis. The
+// caller must hold connectMu.
+func (s *Service) deploymentBlock(ctx context.Context, client *ethclient.Client, m *deployments.Manifest) uint64 {
+	if m.DeploymentBlock != 0 || m.TransactionHash == "" {
+		return m.DeploymentBlock
+	}
+	if block, ok := s.deploymentBlocks[m.Address()]; ok {
+		return block
+	}
+	receipt, err := client.TransactionReceipt(ctx, common.HexToHash(m.TransactionHash))
+	if err != nil {
+		s.Logger.Log(fmt.Sprintf("Deployment block of the data contract: %v", err))
+		return 0
+	}
+	block := receipt.BlockNumber.Uint64()
+	if s.deploymentBlocks == nil {
+		s.deploymentBlocks = make(map[common.Address]uint64)
+	}
+	s.deploymentBlocks[m.Address()] = block
+	s.Logger.Log(fmt.Sprintf("Data contract deployed in block %d", block))
+	return block
+}
+
+// Disconnect closes the contract RPC.
+func (s *Service) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnect()
+}
+
+// disconnect is Disconnect, the caller must hold the write lock.
+func (s *Service) disconnect() {
+	if s.rpcPool != nil {
+		s.rpcPool.Close()
+		s.rpcPool = nil
+	}
+	if s.stopTracking != nil {
+		s.stopTracking()
+		s.stopTracking = nil
+	}
+	s.ethClient = nil
+	s.contract = nil
+}
+
+// Contract returns the data contract, nil if it is not connected.
+func (s *Service) Contract() DataContractInterface {
+	return s.chain().contract
+}
+
+// EthClient returns the contract RPC client, nil if it is not connected.
+func (s *Service) EthClient() *ethclient.Client {
+	return s.chain().client
+}
+
+// ActiveRPC returns the contract RPC endpoint in use, or an empty string.
+func (s *Service) ActiveRPC() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.rpcPool == nil {
+		return ""
+	}
+	return s.rpcPool.Active()
+}
+
+// FailoverRPC switches the contract to the next healthy endpoint.
+func (s *Service) FailoverRPC(ctx context.Context) error {
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	s.mu.RLock()
+	pool := s.rpcPool
+	s.mu.RUnlock()
+	if pool == nil {
+		return ErrNoDataContract
+	}
+	client, err := pool.Failover(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rpcPool != pool {
+		// disconnected meanwhile
+		return ErrNoDataContract
+	}
+	if err != nil {
+		s.ethClient = nil
+		return err
+	}
+	s.ethClient = client
+	s.Logger.Log(fmt.Sprintf("Contract RPC switched to %s", RedactRPCEndpoint(pool.Active())))
+	return nil
+}
+
+func (s *Service) chain() chainConn {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return chainConn{s.contract, s.ethClient, s.contractABI, s.trackCtx}
+}
+
+// connectedContract returns the data contract connection, connecting first
+// if needed. Concurrent callers connect once.
+func (s *Service) connectedContract(ctx context.Context) (chainConn, error) {
+	if c := s.chain(); c.contract != nil {
+		return c, nil
+	}
+	s.connectMu.Lock()
+	defer s.connectMu.Unlock()
+	if c := s.chain(); c.contract != nil {
+		return c, nil
+	}
+	if err := s.connect(ctx); err != nil {
+		return chainConn{}, err
+	}
+	return s.chain(), nil
+}
+
+// SubscribeShares subscribes to the DataSentToTarget events of the data
+// contract, each log is passed on to sink, see ReceiveShare.
+func (s *Service) SubscribeShares(ctx context.Context, sink chan<- types.Log) (ethereum.Subscription, error) {
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.contract.SubscribeDataSentToTarget(ctx, c.client, sink)
+}
+
+// ReceiveShare parses a DataSentToTarget log and saves it as the last share
+// received.
+func (s *Service) ReceiveShare(vLog types.Log) (*Share, error) {
+	share, err := ParseShare(s.chain().abi, vLog)
+	if err != nil {
+		return nil, err
+	}
+	if share.Reference == "" {
+		s.Logger.Log(fmt.Sprintf("Topic string too short (%d chars) to contain publicKey + 32-byte hex", len(share.Topic)))
+	} else {
+		s.Prefs.SetString(EventPublicKeyPrefKey, share.Publisher)
+		s.Prefs.SetString(EventRefPrefKey, share.Reference)
+	}
+	s.Prefs.SetString(EventOwnerPrefKey, hex.EncodeToString(share.Owner))
+	s.Prefs.SetString(EventActRefPrefKey, hex.EncodeToString(share.ActRef))
+	s.Prefs.SetString(EventTopicPrefKey, share.Topic)
+	return share, nil
+}
+
+// LastShare returns the last share saved by ReceiveShare.
+func (s *Service) LastShare() (*Share, error) {
+	share := &Share{
+		Publisher: s.Prefs.String(EventPublicKeyPrefKey),
+		Reference: s.Prefs.String(EventRefPrefKey),
+		Topic:     s.Prefs.String(EventTopicPrefKey),
+	}
+	if share.Reference == "" {
+		return nil, ErrNoShare
+	}
+	var err error
+	if share.Owner, err = hex.DecodeString(s.Prefs.String(EventOwnerPrefKey)); err != nil {
+		return nil, fmt.Errorf("share owner: %w", err)
+	}
+	if share.ActRef, err = hex.DecodeString(s.Prefs.String(EventActRefPrefKey)); err != nil {
+		return nil, fmt.Errorf("share history reference: %w", err)
+	}
+	return share, nil
+}
+
+// SendShare sends ref to target through the data contract, with the node
+// public key as publisher and the saved history reference. The transaction is
+// recorded in the outbox.
+func (s *Service) SendShare(ctx context.Context, target common.Address, ref swarm.Address) (*types.Receipt, error) {
+	receipts, err := s.SendShares(ctx, []common.Address{target}, ref)
+	if err != nil {
+		return nil, err
+	}
+	return receipts[0], nil
+}
+
+// SendShares sends ref to all targets like SendShare, in one transaction if
+// the contract supports it and in one transaction per target otherwise.
+func (s *Service) SendShares(ctx context.Context, targets []common.Address, ref swarm.Address) ([]*types.Receipt, error) {
+	if s.Node() == nil {
+		return nil, ErrNodeNotStarted
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	owner, actRef, topic, err := s.shareData(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targets) == 1 {
+		receipt, err := s.SendData(ctx, targets[0], owner, actRef, topic)
+		if err != nil {
+			return nil, err
+		}
+		return []*types.Receipt{receipt}, nil
+	}
+
+	receipt, err := s.SendDataToTargets(ctx, targets, owner, actRef, topic)
+	switch {
+	case errors.Is(err, ErrBatchUnsupported):
+		s.Logger.Log(fmt.Sprintf("Sending to %d targets one by one: %v", len(targets), err))
+	case err != nil:
+		return nil, err
+	default:
+		shares, err := ParseShares(s.chain().abi, receipt.Logs)
+		if err != nil {
+			return nil, err
+		}
+		if len(shares) != len(targets) {
+			return nil, fmt.Errorf("sent to %d of %d targets in tx %s", len(shares), len(targets), receipt.TxHash.Hex())
+		}
+		return []*types.Receipt{receipt}, nil
+	}
+
+	receipts := make([]*types.Receipt, 0, len(targets))
+	for _, target := range targets {
+		receipt, err := s.SendData(ctx, target, owner, actRef, topic)
+		if err != nil {
+			return receipts, fmt.Errorf("send to %s: %w", target.Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+// shareData returns the owner, history reference and topic that share ref
+// from the node.
+func (s *Service) shareData(ref swarm.Address) (owner, actRef []byte, topic string, err error) {
+	node := s.Node()
+	if node == nil {
+		return nil, nil, "", ErrNodeNotStarted
+	}
+	history := s.HistoryRef()
+	if history.IsZero() {
+		return nil, nil, "", ErrNoHistory
+	}
+	topic = ShareTopic(crypto.FromECDSAPub(node.PublicKey()), ref)
+	return node.OverlayEthAddress().Bytes(), history.Bytes(), topic, nil
+}
+
+// GroupAddresses returns the addresses of the grantees, the targets to notify
+// of content shared with the group.
+func (s *Service) GroupAddresses(ctx context.Context) ([]common.Address, error) {
+	grantees, err := s.Grantees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]common.Address, 0, len(grantees))
+	for _, g := range grantees {
+		pub, err := ParsePublicKey(g)
+		if err != nil {
+			return nil, fmt.Errorf("grantee %s: %w", g, err)
+		}
+		addresses = append(addresses, crypto.PubkeyToAddress(*pub))
+	}
+	return addresses, nil
+}
+
+// Inbox returns the shares sent to the node, oldest first. It only needs the
+// chain, the node does not have to run.
+func (s *Service) Inbox(ctx context.Context) ([]*Share, error) {
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.Address()
+	if err != nil {
+		return nil, err
+	}
+	logs, err := c.contract.FilterDataSentToTarget(ctx, c.client, address)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]*Share, 0, len(logs))
+	for _, vLog := range logs {
+		share, err := ParseShare(c.abi, vLog)
+		if err != nil {
+			s.Logger.Log(fmt.Sprintf("Skipping log %s: %v", vLog.TxHash.Hex(), err))
+			continue
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
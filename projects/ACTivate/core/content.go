@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+// Upload is an entry of the upload history.
+type Upload struct {
+	Name      string
+	Reference string
+	Size      int64
+	Timestamp time.Time
+	Mimetype  string
+}
+
+// BatchHex returns the hex ID of a batch.
+func BatchHex(stamp *postage.StampIssuer) string {
+	return hex.EncodeToString(stamp.ID())
+}
+
+// GroupBatches returns the default batch of each operation group.
+func (s *Service) GroupBatches() (map[string]string, error) {
+	groups := make(map[string]string)
+	if v := s.Prefs.String(GroupBatchesPrefKey); v != "" {
+		if err := json.Unmarshal([]byte(v), &groups); err != nil {
+			return groups, fmt.Errorf("read group batches: %w", err)
+		}
+	}
+	return groups, nil
+}
+
+// SetGroupBatch makes batchHex the default batch of group.
+func (s *Service) SetGroupBatch(group, batchHex string) error {
+	groups, err := s.GroupBatches()
+	if err != nil {
+		s.Logger.Log(err.Error())
+	}
+	groups[group] = batchHex
+	data, err := json.Marshal(groups)
+	if err != nil {
+		return err
+	}
+	s.Prefs.SetString(GroupBatchesPrefKey, string(data))
+	return nil
+}
+
+// BatchPolicy returns the saved batch policy.
+func (s *Service) BatchPolicy() string {
+	return NormalizeBatchPolicy(s.Prefs.String(BatchPolicyPrefKey))
+}
+
+// SelectBatch picks the batch for an operation of group, or the batch with
+// the hex ID batchHex if it is not empty.
+func (s *Service) SelectBatch(group, batchHex string) (*postage.StampIssuer, string, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, "", ErrNodeNotStarted
+	}
+	stamps := node.GetUsableBatches()
+	if batchHex != "" {
+		if stamp := FindBatch(stamps, batchHex); stamp != nil {
+			return stamp, "given", nil
+		}
+		return nil, "", fmt.Errorf("batch %s is not usable", batchHex)
+	}
+	groups, err := s.GroupBatches()
+	if err != nil {
+		s.Logger.Log(err.Error())
+	}
+	return SelectBatch(stamps, s.BatchPolicy(), group, groups[group], s.Prefs.String(BatchPrefKey))
+}
+
+// Upload stores the content of r in Swarm and adds it to the upload history.
+// With act the upload is protected by the access control list, the history
+// reference it returns is saved for sharing.
+func (s *Service) Upload(ctx context.Context, batchHex, name, mimetype string, size int64, act bool, r io.Reader) (swarm.Address, error) {
+	node := s.Node()
+	if node == nil {
+		return swarm.ZeroAddress, ErrNodeNotStarted
+	}
+	history := swarm.ZeroAddress
+	if act {
+		history = s.HistoryRef()
+	}
+	ref, newHistory, err := node.AddFileBzz(ctx, batchHex, name, mimetype, act, history, false, 0, r)
+	if err != nil {
+		return swarm.ZeroAddress, err
+	}
+	s.Logger.Log(fmt.Sprintf("reference of the uploaded file: %s", ref.String()))
+	if act && !newHistory.IsZero() {
+		s.Prefs.SetString(HistoryRefPrefKey, newHistory.String())
+	}
+
+	uploads, err := s.Uploads()
+	if err != nil {
+		return ref, err
+	}
+	uploads = append(uploads, Upload{
+		Name:      name,
+		Reference: ref.String(),
+		Timestamp: time.Now(),
+		Size:      size,
+		Mimetype:  mimetype,
+	})
+	data, err := json.Marshal(uploads)
+	if err != nil {
+		return ref, err
+	}
+	s.Prefs.SetString(UploadsPrefKey, string(data))
+	return ref, nil
+}
+
+// Uploads returns the upload history, oldest first.
+func (s *Service) Uploads() ([]Upload, error) {
+	uploads := []Upload{}
+	if v := s.Prefs.String(UploadsPrefKey); v != "" {
+		if err := json.Unmarshal([]byte(v), &uploads); err != nil {
+			return nil, fmt.Errorf("read upload history: %w", err)
+		}
+	}
+	return uploads, nil
+}
+
+// Download reads the content at ref. publisher and history are needed for
+// content protected by an access control list, nil otherwise.
+func (s *Service) Download(ctx context.Context, ref swarm.Address, publisher *ecdsa.PublicKey, history *swarm.Address) (io.Reader, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	return node.GetBytes(ctx, ref, publisher, history, nil)
+}
+
+// Fetch downloads the content of a share.
+func (s *Service) Fetch(ctx context.Context, share *Share) (io.Reader, error) {
+	if share.Reference == "" {
+		return nil, fmt.Errorf("share in tx %s has no reference", share.TxHash.Hex())
+	}
+	ref, err := swarm.ParseHexAddress(share.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("share reference: %w", err)
+	}
+	var publisher *ecdsa.PublicKey
+	if share.Publisher != "" {
+		if publisher, err = ParsePublicKey(share.Publisher); err != nil {
+			return nil, err
+		}
+	}
+	history := swarm.NewAddress(share.ActRef)
+	return s.Download(ctx, ref, publisher, &history)
+}
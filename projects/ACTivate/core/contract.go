@@ -0,0 +1,408 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethersphere/bee/v2/pkg/sctx"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+const (
+	sendDataToTargetMethod  = "sendDataToTarget"
+	sendDataToTargetsMethod = "sendDataToTargets"
+)
+
+// ErrBatchUnsupported is returned by the batch methods when the deployed
+// contract predates sendDataToTargets.
+var ErrBatchUnsupported = errors.New("the data contract cannot send to several targets at once")
+
+// ErrGasLimitExceeded is returned by EstimateFee when the transaction needs
+// more gas than a block holds.
+var ErrGasLimitExceeded = errors.New("the transaction needs more gas than a block holds, send to fewer targets")
+
+type DataContractInterface interface {
+	SendDataToTarget(ctx context.Context, target common.Address, owner, actRef []byte, topic string) (receipt *types.Receipt, err error)
+	SubmitDataToTarget(ctx context.Context, target common.Address, owner, actRef []byte, topic string) (txHash common.Hash, err error)
+	SendDataToTargets(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (receipt *types.Receipt, err error)
+	SubmitDataToTargets(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (txHash common.Hash, err error)
+	WaitForReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error)
+	ExplainRevert(ctx context.Context, txHash common.Hash) error
+	EstimateFee(ctx context.Context, client *ethclient.Client, targets []common.Address, owner, actRef []byte, topic string, tipBoostPercent int) (*FeeEstimate, error)
+	SubscribeDataSentToTarget(ctx context.Context, client *ethclient.Client, sink chan<- types.Log) (ethereum.Subscription, error)
+	FilterDataSentToTarget(ctx context.Context, client *ethclient.Client, target common.Address) ([]types.Log, error)
+}
+
+type datacontract struct {
+	owner               common.Address
+	dataContractAddress common.Address
+	dataContractABI     abi.ABI
+	transactionService  transaction.Service
+	gasLimit            uint64
+	startBlock          uint64
+	dataSentToTarget    common.Hash
+}
+
+func NewDataContract(
+	owner common.Address,
+	dataContractAddress common.Address,
+	dataContractABI abi.ABI,
+	transactionService transaction.Service,
+	setGasLimit bool,
+	startBlock uint64,
+) DataContractInterface {
+
+	var gasLimit uint64
+	if setGasLimit {
+		gasLimit = transaction.DefaultGasLimit
+	}
+
+	return &datacontract{
+		owner:               owner,
+		dataContractAddress: dataContractAddress,
+		dataContractABI:     dataContractABI,
+		transactionService:  transactionService,
+		gasLimit:            gasLimit,
+		startBlock:          startBlock,
+		dataSentToTarget:    dataContractABI.Events["DataSentToTarget"].ID,
+	}
+}
+
+func (c *datacontract) SendDataToTarget(ctx context.Context, target common.Address, owner, actRef []byte, topic string) (receipt *types.Receipt, err error) {
+	callData, err := c.packData(sendDataToTargetMethod, target, owner, actRef, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err = c.sendTransaction(ctx, callData, sendDataToTargetMethod)
+	if err != nil {
+		return nil, fmt.Errorf("send data to target: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// SubmitDataToTarget sends the transaction without waiting for it to be
+// mined, see WaitForReceipt.
+func (c *datacontract) SubmitDataToTarget(ctx context.Context, target common.Address, owner, actRef []byte, topic string) (txHash common.Hash, err error) {
+	callData, err := c.packData(sendDataToTargetMethod, target, owner, actRef, topic)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err = c.submitTransaction(ctx, callData, sendDataToTargetMethod)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send data to target: %w", err)
+	}
+	return txHash, nil
+}
+
+// SendDataToTargets sends the same data to all targets in one transaction,
+// which emits a DataSentToTarget event per target.
+func (c *datacontract) SendDataToTargets(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (receipt *types.Receipt, err error) {
+	callData, err := c.packData(sendDataToTargetsMethod, targets, owner, actRef, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err = c.sendTransaction(ctx, callData, sendDataToTargetsMethod)
+	if err != nil {
+		return nil, fmt.Errorf("send data to targets: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// SubmitDataToTargets is SendDataToTargets without waiting for the
+// transaction to be mined.
+func (c *datacontract) SubmitDataToTargets(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (txHash common.Hash, err error) {
+	callData, err := c.packData(sendDataToTargetsMethod, targets, owner, actRef, topic)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err = c.submitTransaction(ctx, callData, sendDataToTargetsMethod)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send data to targets: %w", err)
+	}
+	return txHash, nil
+}
+
+// WaitForReceipt waits until txHash is mined. A reverted transaction returns
+// its receipt along with transaction.ErrTransactionReverted.
+func (c *datacontract) WaitForReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	receipt, err = c.transactionService.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == 0 {
+		return receipt, transaction.ErrTransactionReverted
+	}
+	return receipt, nil
+}
+
+// ExplainRevert returns transaction.ErrTransactionReverted with the reason
+// txHash reverted, found by simulating its call again.
+func (c *datacontract) ExplainRevert(ctx context.Context, txHash common.Hash) error {
+	stored, err := c.transactionService.StoredTransaction(txHash)
+	if err != nil {
+		return transaction.ErrTransactionReverted
+	}
+	request := &transaction.TxRequest{
+		To:       stored.To,
+		Data:     stored.Data,
+		GasLimit: stored.GasLimit,
+		Value:    stored.Value,
+	}
+	return c.explain(ctx, request, transaction.ErrTransactionReverted)
+}
+
+// EstimateFee estimates the gas and the EIP-1559 fees of sending to targets
+// the way the transaction service computes them, with the suggested gas price
+// and tip raised by tipBoostPercent. The gas limit is the fixed one of the
+// contract, raised to the estimate plus a margin if that needs more.
+func (c *datacontract) EstimateFee(ctx context.Context, client *ethclient.Client, targets []common.Address, owner, actRef []byte, topic string, tipBoostPercent int) (*FeeEstimate, error) {
+	if client == nil {
+		return nil, errors.New("ethclient.Client is nil")
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	method, target := sendDataToTargetMethod, interface{}(targets[0])
+	if len(targets) > 1 {
+		method, target = sendDataToTargetsMethod, targets
+	}
+	callData, err := c.packData(method, target, owner, actRef, topic)
+	if err != nil {
+		return nil, err
+	}
+	request := c.txRequest(ctx, callData, method)
+
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{
+		From: c.owner,
+		To:   request.To,
+		Data: request.Data,
+	})
+	if err != nil {
+		return nil, c.explain(ctx, request, fmt.Errorf("estimate gas: %w", err))
+	}
+	gasLimit := max(request.GasLimit, gas+gas/4)
+
+	boost := func(v *big.Int) *big.Int {
+		v = new(big.Int).Mul(v, big.NewInt(int64(tipBoostPercent)+100))
+		return v.Div(v, big.NewInt(100))
+	}
+	gasPrice := request.GasPrice
+	if gasPrice == nil {
+		if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("gas price: %w", err)
+		}
+		gasPrice = boost(gasPrice)
+	}
+	tipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gas tip: %w", err)
+	}
+	tipCap = boost(tipCap)
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("latest block: %w", err)
+	}
+	if gasLimit > header.GasLimit {
+		return nil, ErrGasLimitExceeded
+	}
+	baseFee := header.BaseFee
+	if baseFee == nil {
+		baseFee = gasPrice
+	}
+
+	return &FeeEstimate{
+		GasEstimate: gas,
+		GasLimit:    gasLimit,
+		BaseFee:     baseFee,
+		TipCap:      tipCap,
+		FeeCap:      new(big.Int).Add(tipCap, gasPrice),
+	}, nil
+}
+
+// packData packs a call of sendDataToTarget or sendDataToTargets, target is
+// an address or a slice of addresses.
+func (c *datacontract) packData(method string, target interface{}, owner, actRef []byte, topic string) ([]byte, error) {
+	if _, ok := c.dataContractABI.Methods[method]; !ok && method == sendDataToTargetsMethod {
+		return nil, ErrBatchUnsupported
+	}
+
+	// Convert slices to fixed-size arrays as expected by the ABI
+	var ownerArray [32]byte
+	var actRefArray [32]byte
+	copy(ownerArray[:], owner)
+	copy(actRefArray[:], actRef)
+
+	return c.dataContractABI.Pack(method, target, ownerArray, actRefArray, topic)
+}
+
+// SubscribeDataSentToTarget delivers the DataSentToTarget events mined from
+// the start block on to sink until ctx is done, the current block when there
+// is no start block. A live subscription only sees new blocks, so the events
+// up to the head are filtered first and live events of those blocks are
+// dropped. sink is closed when the subscription ends.
+func (c *datacontract) SubscribeDataSentToTarget(ctx context.Context, client *ethclient.Client, sink chan<- types.Log) (ethereum.Subscription, error) {
+	if client == nil {
+		return nil, errors.New("ethclient.Client is nil")
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	fromBlock := c.startBlock
+	if fromBlock == 0 {
+		fromBlock = head + 1
+	}
+	log.Printf("Subscribing to DataContract DataSentToTarget events from block %d", fromBlock)
+
+	topics := [][]common.Hash{{c.dataSentToTarget}}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{c.dataContractAddress},
+		Topics:    topics,
+		FromBlock: new(big.Int).SetUint64(head + 1),
+	}
+
+	logs := make(chan types.Log)
+
+	// subscribe before filtering, so that no block is missed in between
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to DataSentToTarget events: %w", err)
+	}
+
+	go func() {
+		defer close(sink)
+		defer sub.Unsubscribe()
+		send := func(vLog types.Log) bool {
+			select {
+			case sink <- vLog:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		past, err := c.filterLogs(ctx, client, topics, fromBlock, head)
+		if err != nil {
+			log.Printf("DataSentToTarget backfill failed: %v", err)
+			return
+		}
+		for _, vLog := range past {
+			if !send(vLog) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err != nil {
+					log.Printf("DataSentToTarget subscription failed: %v", err)
+				}
+				return
+			case vLog := <-logs:
+				if vLog.BlockNumber <= head {
+					continue
+				}
+				if !send(vLog) {
+					return
+				}
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// FilterDataSentToTarget returns the DataSentToTarget events sent to target
+// since the contract was deployed, oldest first.
+func (c *datacontract) FilterDataSentToTarget(ctx context.Context, client *ethclient.Client, target common.Address) ([]types.Log, error) {
+	if client == nil {
+		return nil, errors.New("ethclient.Client is nil")
+	}
+
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return c.filterLogs(ctx, client, [][]common.Hash{{c.dataSentToTarget}, nil, {common.BytesToHash(target.Bytes())}}, c.startBlock, latest)
+}
+
+// filterLogs returns the events of the contract matching topics in the
+// blocks from to to, oldest first.
+func (c *datacontract) filterLogs(ctx context.Context, client *ethclient.Client, topics [][]common.Hash, from, to uint64) ([]types.Log, error) {
+	// providers limit the block range of a single query
+	const blockPageSize = 10000
+	var logs []types.Log
+	for ; from <= to; from += blockPageSize {
+		last := min(from+blockPageSize-1, to)
+		page, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			Addresses: []common.Address{c.dataContractAddress},
+			Topics:    topics,
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(last),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter DataSentToTarget events in blocks %d-%d: %w", from, last, err)
+		}
+		logs = append(logs, page...)
+	}
+	return logs, nil
+}
+
+func (c *datacontract) txRequest(ctx context.Context, callData []byte, desc string) *transaction.TxRequest {
+	return &transaction.TxRequest{
+		To:          &c.dataContractAddress,
+		Data:        callData,
+		GasPrice:    sctx.GetGasPrice(ctx),
+		GasLimit:    max(sctx.GetGasLimit(ctx), c.gasLimit),
+		Value:       big.NewInt(0),
+		Description: desc,
+	}
+}
+
+func (c *datacontract) submitTransaction(ctx context.Context, callData []byte, desc string) (txHash common.Hash, err error) {
+	request := c.txRequest(ctx, callData, desc)
+
+	defer func() {
+		err = c.explain(ctx, request, err)
+	}()
+
+	return c.transactionService.Send(ctx, request, SpeedFrom(ctx).TipBoostPercent())
+}
+
+func (c *datacontract) sendTransaction(ctx context.Context, callData []byte, desc string) (receipt *types.Receipt, err error) {
+	request := c.txRequest(ctx, callData, desc)
+
+	defer func() {
+		err = c.explain(ctx, request, err)
+	}()
+
+	txHash, err := c.transactionService.Send(ctx, request, SpeedFrom(ctx).TipBoostPercent())
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err = c.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
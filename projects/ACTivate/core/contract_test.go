@@ -0,0 +1,282 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+// testShare returns the owner, history reference and topic of a share.
+func testShare(t *testing.T) (owner, actRef []byte, topic string) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner = bytes.Repeat([]byte{0x0a}, 32)
+	actRef = bytes.Repeat([]byte{0x0b}, 32)
+	reference := swarm.MustParseHexAddress("c0ffee" + string(bytes.Repeat([]byte("0"), 58)))
+	return owner, actRef, ShareTopic(crypto.FromECDSAPub(&key.PublicKey), reference)
+}
+
+func checkShare(t *testing.T, share *Share, from, to common.Address, owner, actRef []byte, topic string) {
+	t.Helper()
+	if share.From != from {
+		t.Errorf("from = %s, want %s", share.From.Hex(), from.Hex())
+	}
+	if share.To != to {
+		t.Errorf("to = %s, want %s", share.To.Hex(), to.Hex())
+	}
+	if !bytes.Equal(share.Owner, owner) {
+		t.Errorf("owner = %x, want %x", share.Owner, owner)
+	}
+	if !bytes.Equal(share.ActRef, actRef) {
+		t.Errorf("actref = %x, want %x", share.ActRef, actRef)
+	}
+	if share.Topic != topic {
+		t.Errorf("topic = %s, want %s", share.Topic, topic)
+	}
+	publisher, reference, _ := SplitShareTopic(topic)
+	if share.Publisher != publisher || share.Reference != reference {
+		t.Errorf("publisher, reference = %s, %s, want %s, %s", share.Publisher, share.Reference, publisher, reference)
+	}
+}
+
+func TestSendDataToTarget(t *testing.T) {
+	c := newSimChain(t)
+	ctx := context.Background()
+	owner, actRef, topic := testShare(t)
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	receipt, err := c.contract.SendDataToTarget(ctx, target, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("receipt status = %d", receipt.Status)
+	}
+	shares, err := ParseShares(c.abi, receipt.Logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 1 {
+		t.Fatalf("got %d shares, want 1", len(shares))
+	}
+	checkShare(t, shares[0], c.Sender, target, owner, actRef, topic)
+	if shares[0].TxHash != receipt.TxHash || shares[0].BlockNumber != receipt.BlockNumber.Uint64() {
+		t.Errorf("share of tx %s in block %d, want %s in block %d", shares[0].TxHash.Hex(), shares[0].BlockNumber, receipt.TxHash.Hex(), receipt.BlockNumber)
+	}
+}
+
+func TestSendDataToTargetZeroAddress(t *testing.T) {
+	c := newSimChain(t)
+	owner, actRef, topic := testShare(t)
+
+	_, err := c.contract.SendDataToTarget(context.Background(), common.Address{}, owner, actRef, topic)
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) {
+		t.Fatalf("got error %v, want a *RevertError", err)
+	}
+	if want := "DataContract: target cannot be zero address"; revertErr.Reason != want {
+		t.Errorf("reason = %q, want %q", revertErr.Reason, want)
+	}
+}
+
+func TestSendDataToTargets(t *testing.T) {
+	c := newSimChain(t)
+	ctx := context.Background()
+	owner, actRef, topic := testShare(t)
+	targets := []common.Address{
+		common.HexToAddress("0x1000000000000000000000000000000000000001"),
+		common.HexToAddress("0x1000000000000000000000000000000000000002"),
+		common.HexToAddress("0x1000000000000000000000000000000000000003"),
+	}
+
+	receipt, err := c.contract.SendDataToTargets(ctx, targets, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := ParseShares(c.abi, receipt.Logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != len(targets) {
+		t.Fatalf("got %d shares, want %d", len(shares), len(targets))
+	}
+	for i, share := range shares {
+		checkShare(t, share, c.Sender, targets[i], owner, actRef, topic)
+	}
+
+	_, err = c.contract.SendDataToTargets(ctx, []common.Address{}, owner, actRef, topic)
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) || revertErr.Reason != "DataContract: no targets" {
+		t.Errorf("got error %v, want the no targets revert", err)
+	}
+}
+
+func TestFilterDataSentToTarget(t *testing.T) {
+	c := newSimChain(t)
+	ctx := context.Background()
+	owner, actRef, topic := testShare(t)
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000002")
+
+	var want []common.Hash
+	for _, to := range []common.Address{target, other, target} {
+		receipt, err := c.contract.SendDataToTarget(ctx, to, owner, actRef, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if to == target {
+			want = append(want, receipt.TxHash)
+		}
+	}
+
+	logs, err := c.contract.FilterDataSentToTarget(ctx, c.Client, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("got %d logs, want %d", len(logs), len(want))
+	}
+	for i, vLog := range logs {
+		if vLog.TxHash != want[i] {
+			t.Errorf("log %d of tx %s, want %s", i, vLog.TxHash.Hex(), want[i].Hex())
+		}
+		share, err := ParseShare(c.abi, vLog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkShare(t, share, c.Sender, target, owner, actRef, topic)
+	}
+}
+
+func TestSubscribeDataSentToTarget(t *testing.T) {
+	c := newSimChain(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	owner, actRef, topic := testShare(t)
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	sink := make(chan types.Log)
+	sub, err := c.contract.SubscribeDataSentToTarget(ctx, c.Client, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	receipt, err := c.contract.SendDataToTarget(ctx, target, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case vLog, ok := <-sink:
+		if !ok {
+			t.Fatal("subscription ended without a log")
+		}
+		if vLog.TxHash != receipt.TxHash {
+			t.Errorf("log of tx %s, want %s", vLog.TxHash.Hex(), receipt.TxHash.Hex())
+		}
+		share, err := ParseShare(c.abi, vLog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkShare(t, share, c.Sender, target, owner, actRef, topic)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no log delivered")
+	}
+}
+
+// TestSubscribeDataSentToTargetBackfill subscribes after events were mined
+// from the start block on, they are delivered once before the live ones.
+func TestSubscribeDataSentToTargetBackfill(t *testing.T) {
+	c := newSimChain(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	owner, actRef, topic := testShare(t)
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	var want []common.Hash
+	for range 2 {
+		receipt, err := c.contract.SendDataToTarget(ctx, target, owner, actRef, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, receipt.TxHash)
+	}
+
+	contract := NewDataContract(c.Sender, c.Address, c.abi, c.Transactions, true, 1)
+	sink := make(chan types.Log)
+	sub, err := contract.SubscribeDataSentToTarget(ctx, c.Client, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	receipt, err := c.contract.SendDataToTarget(ctx, target, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, receipt.TxHash)
+
+	for i := range want {
+		select {
+		case vLog, ok := <-sink:
+			if !ok {
+				t.Fatalf("subscription ended after %d logs", i)
+			}
+			if vLog.TxHash != want[i] {
+				t.Errorf("log %d of tx %s, want %s", i, vLog.TxHash.Hex(), want[i].Hex())
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%d logs delivered, want %d", i, len(want))
+		}
+	}
+	select {
+	case vLog := <-sink:
+		t.Errorf("log of tx %s delivered twice", vLog.TxHash.Hex())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	c := newSimChain(t)
+	ctx := context.Background()
+	owner, actRef, topic := testShare(t)
+	targets := []common.Address{common.HexToAddress("0x1000000000000000000000000000000000000001")}
+
+	fee, err := c.contract.EstimateFee(ctx, c.Client, targets, owner, actRef, topic, SpeedNormal.TipBoostPercent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.GasEstimate == 0 || fee.GasLimit < fee.GasEstimate {
+		t.Errorf("gas estimate %d with limit %d", fee.GasEstimate, fee.GasLimit)
+	}
+	if fee.Expected().Sign() <= 0 || fee.Max().Cmp(fee.Expected()) < 0 {
+		t.Errorf("expected cost %s, at most %s", fee.Expected(), fee.Max())
+	}
+	if want := new(big.Int).Mul(fee.FeeCap, new(big.Int).SetUint64(fee.GasEstimate)); fee.Max().Cmp(want) != 0 {
+		t.Errorf("at most %s, want the estimated gas at the fee cap %s", fee.Max(), want)
+	}
+	if fee.Upfront().Cmp(fee.Max()) < 0 {
+		t.Errorf("upfront %s below the maximum cost %s", fee.Upfront(), fee.Max())
+	}
+
+	if _, err := c.contract.EstimateFee(ctx, c.Client, nil, owner, actRef, topic, 0); !errors.Is(err, ErrNoTargets) {
+		t.Errorf("got error %v without targets, want %v", err, ErrNoTargets)
+	}
+
+	_, err = c.contract.EstimateFee(ctx, c.Client, []common.Address{{}}, owner, actRef, topic, 0)
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) {
+		t.Errorf("got error %v, want a *RevertError", err)
+	}
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+// Speed sets how much the fees of a transaction are raised over the ones
+// suggested by the RPC, so that it is mined sooner.
+type Speed string
+
+const (
+	SpeedSlow   Speed = "slow"
+	SpeedNormal Speed = "normal"
+	SpeedFast   Speed = "fast"
+)
+
+// Speeds are the names of the speeds, for selects and flags.
+var Speeds = []string{string(SpeedSlow), string(SpeedNormal), string(SpeedFast)}
+
+var ErrUnknownSpeed = errors.New("unknown speed, use slow, normal or fast")
+
+// ParseSpeed returns the speed named s, or SpeedNormal for an empty s.
+func ParseSpeed(s string) (Speed, error) {
+	switch Speed(s) {
+	case "":
+		return SpeedNormal, nil
+	case SpeedSlow, SpeedNormal, SpeedFast:
+		return Speed(s), nil
+	}
+	return "", ErrUnknownSpeed
+}
+
+// TipBoostPercent is the raise of the suggested gas price and tip.
+func (sp Speed) TipBoostPercent() int {
+	switch sp {
+	case SpeedSlow:
+		return 0
+	case SpeedFast:
+		return 50
+	}
+	return transaction.DefaultTipBoostPercent
+}
+
+type speedKey struct{}
+
+// WithSpeed returns a context whose transactions are sent at speed, like
+// sctx.SetGasPrice does for the gas price.
+func WithSpeed(ctx context.Context, speed Speed) context.Context {
+	return context.WithValue(ctx, speedKey{}, speed)
+}
+
+// SpeedFrom returns the speed set by WithSpeed, SpeedNormal by default.
+func SpeedFrom(ctx context.Context) Speed {
+	if v, ok := ctx.Value(speedKey{}).(Speed); ok {
+		return v
+	}
+	return SpeedNormal
+}
+
+// FeeEstimate is the expected cost of a transaction. The fees follow the
+// EIP-1559 fields the transaction service will use: the tip and the fee cap
+// include the boost of the speed.
+type FeeEstimate struct {
+	Speed       Speed
+	GasEstimate uint64
+	GasLimit    uint64
+	BaseFee     *big.Int
+	TipCap      *big.Int
+	FeeCap      *big.Int
+	Balance     *big.Int
+}
+
+// Expected is the likely cost in wei, the estimated gas at the current base
+// fee plus the tip.
+func (f *FeeEstimate) Expected() *big.Int {
+	price := new(big.Int).Add(f.BaseFee, f.TipCap)
+	if price.Cmp(f.FeeCap) > 0 {
+		price = f.FeeCap
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(f.GasEstimate))
+}
+
+// Max is the most the transaction can cost in wei, the estimated gas at the
+// fee cap. The gas limit is not used up, only the gas the call needs is paid.
+func (f *FeeEstimate) Max() *big.Int {
+	return new(big.Int).Mul(f.FeeCap, new(big.Int).SetUint64(f.GasEstimate))
+}
+
+// Upfront is the balance the transaction needs to be accepted, the whole gas
+// limit at the fee cap.
+func (f *FeeEstimate) Upfront() *big.Int {
+	return new(big.Int).Mul(f.FeeCap, new(big.Int).SetUint64(f.GasLimit))
+}
+
+// Affordable reports whether the balance covers the upfront cost.
+func (f *FeeEstimate) Affordable() bool {
+	return f.Balance != nil && f.Balance.Cmp(f.Upfront()) >= 0
+}
+
+// EstimateData estimates the cost of SendData or SendDataToTargets at the
+// speed of ctx, and reads the balance that pays for it.
+func (s *Service) EstimateData(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (*FeeEstimate, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	boost := SpeedFrom(ctx).TipBoostPercent()
+	estimate, err := c.contract.EstimateFee(ctx, c.client, targets, owner, actRef, topic, boost)
+	if errors.Is(err, ErrBatchUnsupported) {
+		// SendShares falls back to a transaction per target
+		estimate, err = c.contract.EstimateFee(ctx, c.client, targets[:1], owner, actRef, topic, boost)
+		if err == nil {
+			n := uint64(len(targets))
+			estimate.GasEstimate *= n
+			estimate.GasLimit *= n
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	estimate.Speed = SpeedFrom(ctx)
+	if estimate.Balance, err = c.client.BalanceAt(ctx, node.OverlayEthAddress(), nil); err != nil {
+		return nil, fmt.Errorf("balance: %w", err)
+	}
+	return estimate, nil
+}
+
+// EstimateShares estimates the cost of SendShares at the speed of ctx.
+func (s *Service) EstimateShares(ctx context.Context, targets []common.Address, ref swarm.Address) (*FeeEstimate, error) {
+	owner, actRef, topic, err := s.shareData(ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.EstimateData(ctx, targets, owner, actRef, topic)
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+var ErrNoGranteeList = errors.New("no grantee list yet, create one first")
+
+// GranteeList is the state of the access control list after an update.
+type GranteeList struct {
+	Ref        swarm.Address
+	HistoryRef swarm.Address
+}
+
+// GranteeListRef returns the saved encrypted grantee list reference, zero if
+// there is none.
+func (s *Service) GranteeListRef() swarm.Address {
+	return s.savedAddress(EglrefPrefKey)
+}
+
+// HistoryRef returns the saved history reference, zero if there is none.
+func (s *Service) HistoryRef() swarm.Address {
+	return s.savedAddress(HistoryRefPrefKey)
+}
+
+func (s *Service) saveGranteeList(l *GranteeList) {
+	s.Prefs.SetString(EglrefPrefKey, l.Ref.String())
+	s.Prefs.SetString(HistoryRefPrefKey, l.HistoryRef.String())
+}
+
+// Grantees lists the public keys in the saved grantee list.
+func (s *Service) Grantees(ctx context.Context) ([]string, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	eglRef := s.GranteeListRef()
+	if eglRef.IsZero() {
+		return nil, ErrNoGranteeList
+	}
+	return node.GetGranteeList(ctx, eglRef, false)
+}
+
+// CreateGroup creates a new grantee list and saves it in place of the
+// current one.
+func (s *Service) CreateGroup(ctx context.Context, batchHex string, grantees []string) (*GranteeList, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	ref, history, err := node.CreateGrantees(ctx, batchHex, swarm.ZeroAddress, grantees)
+	if err != nil {
+		return nil, fmt.Errorf("create grantee list: %w", err)
+	}
+	l := &GranteeList{Ref: ref, HistoryRef: history}
+	s.saveGranteeList(l)
+	return l, nil
+}
+
+// UpdateGroup adds and revokes grantees of the saved grantee list, on top of
+// history. Adding without a saved list creates one.
+func (s *Service) UpdateGroup(ctx context.Context, batchHex string, history swarm.Address, add, revoke []string) (*GranteeList, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	eglRef := s.GranteeListRef()
+	if add == nil {
+		add = []string{}
+	}
+	if revoke == nil {
+		revoke = []string{}
+	}
+
+	var l GranteeList
+	var err error
+	switch {
+	case eglRef.IsZero() && len(revoke) != 0:
+		return nil, ErrNoGranteeList
+	case eglRef.IsZero():
+		s.Logger.Log(fmt.Sprintf("Calling CreateGrantees with History: %s, Grantees: %v", history, add))
+		l.Ref, l.HistoryRef, err = node.CreateGrantees(ctx, batchHex, history, add)
+	default:
+		s.Logger.Log(fmt.Sprintf("Calling AddRevokeGrantees with EGL: %s, History: %s, Add: %v, Revoke: %v", eglRef, history, add, revoke))
+		l.Ref, l.HistoryRef, err = node.AddRevokeGrantees(ctx, batchHex, eglRef, history, add, revoke)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update grantee list: %w", err)
+	}
+	s.Logger.Log(fmt.Sprintf("Grantee list updated. New EGL Ref: %s, New History Ref: %s", l.Ref, l.HistoryRef))
+	s.saveGranteeList(&l)
+	return &l, nil
+}
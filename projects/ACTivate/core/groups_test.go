@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"activate/core/mock"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+var _ BeeNode = (*mock.Node)(nil)
+
+// newTestService returns a service started on a node of network, with a
+// usable batch whose hex ID is returned too.
+func newTestService(t *testing.T, network *mock.Network) (*Service, *mock.Node, string) {
+	t.Helper()
+	node, err := network.NewNode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	prefs, err := OpenFilePreferences(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(dir, prefs, nil)
+	s.NewNode = func(opts *NodeOptions, password string) (BeeNode, error) {
+		return node, nil
+	}
+	if err := s.Start(&NodeOptions{SwapEnable: true, Network: FindNetworkProfile(DefaultNetwork)}, ""); err != nil {
+		t.Fatal(err)
+	}
+	return s, node, hex.EncodeToString(node.AddBatch("test", 20))
+}
+
+func upload(t *testing.T, s *Service, batchHex, text string) swarm.Address {
+	t.Helper()
+	ref, err := s.Upload(context.Background(), batchHex, "note.txt", "text/plain", int64(len(text)), true, strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+// download reads ref from publisher with history like a grantee does with a
+// received share.
+func download(s *Service, publisher *mock.Node, ref, history swarm.Address) (string, error) {
+	r, err := s.Download(context.Background(), ref, publisher.PublicKey(), &history)
+	if err != nil {
+		return "", err
+	}
+	b, err := io.ReadAll(r)
+	return string(b), err
+}
+
+func TestGroupWorkflow(t *testing.T) {
+	ctx := context.Background()
+	network := mock.NewNetwork()
+	alice, aliceNode, batch := newTestService(t, network)
+	bob, bobNode, _ := newTestService(t, network)
+	carol, carolNode, _ := newTestService(t, network)
+
+	l, err := alice.CreateGroup(ctx, batch, []string{bobNode.PublicKeyHex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alice.GranteeListRef().Equal(l.Ref) || !alice.HistoryRef().Equal(l.HistoryRef) {
+		t.Fatal("the grantee list is not saved")
+	}
+	grantees, err := alice.Grantees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(grantees, []string{bobNode.PublicKeyHex()}) {
+		t.Errorf("grantees = %v, want bob", grantees)
+	}
+
+	ref := upload(t, alice, batch, "for the group")
+	if got, err := download(bob, aliceNode, ref, alice.HistoryRef()); err != nil || got != "for the group" {
+		t.Errorf("bob downloads %q, %v", got, err)
+	}
+	if _, err := download(carol, aliceNode, ref, alice.HistoryRef()); !errors.Is(err, mock.ErrAccessDenied) {
+		t.Errorf("carol downloads with error %v, want access denied", err)
+	}
+	if _, err := bob.Download(ctx, ref, nil, nil); !errors.Is(err, mock.ErrAccessDenied) {
+		t.Errorf("download without history: %v, want access denied", err)
+	}
+
+	// carol joins, bob leaves
+	if _, err := alice.UpdateGroup(ctx, batch, alice.HistoryRef(), []string{carolNode.PublicKeyHex()}, []string{bobNode.PublicKeyHex()}); err != nil {
+		t.Fatal(err)
+	}
+	grantees, err = alice.Grantees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(grantees, []string{carolNode.PublicKeyHex()}) {
+		t.Errorf("grantees = %v, want carol", grantees)
+	}
+	ref = upload(t, alice, batch, "after the change")
+	if got, err := download(carol, aliceNode, ref, alice.HistoryRef()); err != nil || got != "after the change" {
+		t.Errorf("carol downloads %q, %v", got, err)
+	}
+	if _, err := download(bob, aliceNode, ref, alice.HistoryRef()); !errors.Is(err, mock.ErrAccessDenied) {
+		t.Errorf("revoked bob downloads with error %v, want access denied", err)
+	}
+	if got, err := download(alice, aliceNode, ref, alice.HistoryRef()); err != nil || got != "after the change" {
+		t.Errorf("the publisher downloads %q, %v", got, err)
+	}
+}
+
+func TestUpdateGroup(t *testing.T) {
+	ctx := context.Background()
+	network := mock.NewNetwork()
+	alice, _, batch := newTestService(t, network)
+	_, bobNode, _ := newTestService(t, network)
+
+	if _, err := alice.Grantees(ctx); !errors.Is(err, ErrNoGranteeList) {
+		t.Errorf("grantees without a list: %v, want ErrNoGranteeList", err)
+	}
+	if _, err := alice.UpdateGroup(ctx, batch, swarm.ZeroAddress, nil, []string{bobNode.PublicKeyHex()}); !errors.Is(err, ErrNoGranteeList) {
+		t.Errorf("revoke without a list: %v, want ErrNoGranteeList", err)
+	}
+
+	// adding without a list creates one
+	l, err := alice.UpdateGroup(ctx, batch, alice.HistoryRef(), []string{bobNode.PublicKeyHex()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alice.GranteeListRef().Equal(l.Ref) {
+		t.Error("the created grantee list is not saved")
+	}
+	if _, err := alice.UpdateGroup(ctx, "ffff", alice.HistoryRef(), nil, []string{bobNode.PublicKeyHex()}); !errors.Is(err, mock.ErrUnknownBatch) {
+		t.Errorf("update with an unknown batch: %v", err)
+	}
+	if !alice.GranteeListRef().Equal(l.Ref) {
+		t.Error("a failed update changed the saved grantee list")
+	}
+}
+
+func TestUpload(t *testing.T) {
+	ctx := context.Background()
+	alice, _, batch := newTestService(t, mock.NewNetwork())
+
+	ref, err := alice.Upload(ctx, batch, "public.txt", "text/plain", 6, false, strings.NewReader("public"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alice.HistoryRef().IsZero() {
+		t.Error("an upload without access control saved a history")
+	}
+	r, err := alice.Download(ctx, ref, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "public" {
+		t.Errorf("downloaded %q", b)
+	}
+
+	if _, err := alice.Upload(ctx, "ffff", "lost.txt", "text/plain", 4, false, strings.NewReader("lost")); !errors.Is(err, mock.ErrUnknownBatch) {
+		t.Errorf("upload with an unknown batch: %v", err)
+	}
+	uploads, err := alice.Uploads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].Reference != ref.String() {
+		t.Errorf("uploads = %+v, want the one of %s", uploads, ref)
+	}
+}
+
+func TestSelectBatch(t *testing.T) {
+	alice, node, batch := newTestService(t, mock.NewNetwork())
+
+	stamp, _, err := alice.SelectBatch("", batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(stamp.ID()) != batch {
+		t.Errorf("selected %x, want %s", stamp.ID(), batch)
+	}
+	if _, _, err := alice.SelectBatch("", "ffff"); err == nil {
+		t.Error("selected a batch that is not usable")
+	}
+
+	_, id, err := node.BuyStamp(nil, 22, "bought", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FindBatch(node.GetUsableBatches(), hex.EncodeToString(id)) == nil {
+		t.Error("the bought batch is not usable")
+	}
+}
+
+func TestStopNode(t *testing.T) {
+	ctx := context.Background()
+	alice, _, batch := newTestService(t, mock.NewNetwork())
+
+	if !alice.CanStop() {
+		t.Fatal("the node cannot stop")
+	}
+	if err := alice.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if alice.Node() != nil {
+		t.Error("the stopped node is still running")
+	}
+	if _, err := alice.CreateGroup(ctx, batch, []string{}); !errors.Is(err, ErrNodeNotStarted) {
+		t.Errorf("create group on a stopped node: %v, want ErrNodeNotStarted", err)
+	}
+}
+
+func TestUltraLightOnly(t *testing.T) {
+	node, err := mock.NewNetwork().NewNode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	prefs, err := OpenFilePreferences(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(dir, prefs, nil)
+	s.NewNode = func(opts *NodeOptions, password string) (BeeNode, error) {
+		return node, nil
+	}
+	chiado := FindNetworkProfile(ChiadoNetwork)
+
+	if err := s.Start(&NodeOptions{SwapEnable: true, RPCEndpoint: chiado.RPCEndpoint, Network: chiado}, ""); !errors.Is(err, ErrLightModeUnsupported) {
+		t.Errorf("light mode on %s: %v, want %v", chiado.Name, err, ErrLightModeUnsupported)
+	}
+	if s.Node() != nil {
+		t.Error("the node started in light mode")
+	}
+
+	prefs.SetString(NetworkPrefKey, ChiadoNetwork)
+	prefs.SetString(RPCEndpointPrefKey, chiado.RPCEndpoint)
+	prefs.SetBool(SwapEnablePrefKey, true)
+	if opts := NodeOptionsFromPreferences(prefs, dir); opts.SwapEnable {
+		t.Errorf("saved options on %s enable swap", chiado.Name)
+	}
+}
@@ -0,0 +1,480 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"activate/contract/deployments"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethersphere/bee/v2/pkg/sctx"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+// bytecodeFile is the creation code of DataContract written by `npm run
+// export:bytecode`, relative to the contract directory. NewChain deploys it
+// when present, and an assembled equivalent otherwise.
+const bytecodeFile = "deployments/datacontract-bytecode.hex"
+
+// Chain is a simulated chain with the data contract deployed, reached over
+// IPC so that the client is a real *ethclient.Client.
+type Chain struct {
+	Backend *simulated.Backend
+	// IPCPath is the endpoint of the chain, for the RPC settings of a node.
+	IPCPath string
+	Client  *ethclient.Client
+	Key     *ecdsa.PrivateKey
+	Sender  common.Address
+	// Address is the address of the data contract.
+	Address common.Address
+	// Transactions sends the transactions of Sender, for Node.Transactions.
+	Transactions *ChainTransactions
+}
+
+// NewChain starts a simulated chain that funds a new sender and deploys the
+// data contract. The chain is closed when tb ends.
+func NewChain(tb testing.TB) *Chain {
+	tb.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	ipcPath := filepath.Join(tb.TempDir(), "sim.ipc")
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		sender: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))},
+	}, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.IPCPath = ipcPath
+	})
+	tb.Cleanup(func() { _ = backend.Close() })
+	client, err := ethclient.Dial(ipcPath)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(client.Close)
+
+	contractABI, err := deployments.DefaultABI()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	c := &Chain{
+		Backend: backend,
+		IPCPath: ipcPath,
+		Client:  client,
+		Key:     key,
+		Sender:  sender,
+	}
+	c.Transactions = &ChainTransactions{chain: c, stored: map[common.Hash]*transaction.StoredTransaction{}}
+	c.Address = c.deploy(tb, dataContractCode(tb, contractABI))
+	return c
+}
+
+// deploy sends code as a contract creation and returns the contract address.
+func (c *Chain) deploy(tb testing.TB, code []byte) common.Address {
+	tb.Helper()
+	ctx := context.Background()
+	tx, err := c.SignTx(ctx, &types.DynamicFeeTx{Gas: 3_000_000, Data: code})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := c.Client.SendTransaction(ctx, tx); err != nil {
+		tb.Fatal(err)
+	}
+	c.Backend.Commit()
+	receipt, err := c.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		tb.Fatal("deploying the data contract failed")
+	}
+	return receipt.ContractAddress
+}
+
+// SignTx fills in the chain ID, nonce and fees of tx and signs it with the
+// key of the sender.
+func (c *Chain) SignTx(ctx context.Context, tx *types.DynamicFeeTx) (*types.Transaction, error) {
+	chainID, err := c.Client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := c.Client.PendingNonceAt(ctx, c.Sender)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := c.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	head, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	tx.ChainID = chainID
+	tx.Nonce = nonce
+	tx.GasTipCap = tip
+	tx.GasFeeCap = new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	return types.SignTx(types.NewTx(tx), types.LatestSignerForChainID(chainID), c.Key)
+}
+
+// ChainTransactions is the part of the bee transaction service the data
+// contract uses. Every transaction is mined as soon as it is sent.
+type ChainTransactions struct {
+	transaction.Service
+	chain *Chain
+
+	// Hold leaves sent transactions pending until the backend commits a
+	// block, instead of mining each one at once.
+	Hold bool
+
+	mu     sync.Mutex
+	stored map[common.Hash]*transaction.StoredTransaction
+}
+
+func (s *ChainTransactions) Send(ctx context.Context, request *transaction.TxRequest, tipCapBoostPercent int) (common.Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gasLimit := request.GasLimit
+	if gasLimit == 0 {
+		var err error
+		gasLimit, err = s.chain.Client.EstimateGas(ctx, ethereum.CallMsg{From: s.chain.Sender, To: request.To, Data: request.Data})
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+	tx, err := s.chain.SignTx(ctx, &types.DynamicFeeTx{
+		To:    request.To,
+		Gas:   gasLimit,
+		Value: request.Value,
+		Data:  request.Data,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.send(ctx, tx, tipCapBoostPercent, request.Description)
+}
+
+// CancelTransaction sends a transfer of nothing to the sender at the nonce of
+// txHash, with the fees the bee transaction service picks: the gas price of
+// ctx and the suggested tip, at least the stored ones, the tip raised by 10%.
+func (s *ChainTransactions) CancelTransaction(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.stored[txHash]
+	if !ok {
+		return common.Hash{}, transaction.ErrUnknownTransaction
+	}
+	chainID, err := s.chain.Client.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	price := sctx.GetGasPrice(ctx)
+	if price == nil {
+		if price, err = s.chain.Client.SuggestGasPrice(ctx); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	tip, err := s.chain.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	feeCap := new(big.Int).Add(tip, price)
+	if feeCap.Cmp(stored.GasFeeCap) <= 0 {
+		feeCap.Set(stored.GasFeeCap)
+	}
+	if tip.Cmp(stored.GasTipCap) <= 0 {
+		tip = stored.GasTipCap
+	}
+	tip = new(big.Int).Div(new(big.Int).Mul(tip, big.NewInt(110)), big.NewInt(100))
+	feeCap.Add(feeCap, tip)
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     stored.Nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       21000,
+		To:        &s.chain.Sender,
+		Value:     big.NewInt(0),
+	}), types.LatestSignerForChainID(chainID), s.chain.Key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.send(ctx, tx, stored.GasTipBoost, fmt.Sprintf("%s (cancellation)", stored.Description))
+}
+
+// send sends tx and stores it, the caller must hold mu.
+func (s *ChainTransactions) send(ctx context.Context, tx *types.Transaction, tipCapBoostPercent int, description string) (common.Hash, error) {
+	if err := s.chain.Client.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	s.stored[tx.Hash()] = &transaction.StoredTransaction{
+		To:          tx.To(),
+		Data:        tx.Data(),
+		GasLimit:    tx.Gas(),
+		GasTipBoost: tipCapBoostPercent,
+		GasTipCap:   tx.GasTipCap(),
+		GasFeeCap:   tx.GasFeeCap(),
+		Value:       tx.Value(),
+		Nonce:       tx.Nonce(),
+		Description: description,
+	}
+	if !s.Hold {
+		s.chain.Backend.Commit()
+	}
+	return tx.Hash(), nil
+}
+
+func (s *ChainTransactions) Call(ctx context.Context, request *transaction.TxRequest) ([]byte, error) {
+	return s.chain.Client.CallContract(ctx, ethereum.CallMsg{
+		From:  s.chain.Sender,
+		To:    request.To,
+		Data:  request.Data,
+		Value: request.Value,
+	}, nil)
+}
+
+func (s *ChainTransactions) WaitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return s.chain.Client.TransactionReceipt(ctx, txHash)
+}
+
+func (s *ChainTransactions) StoredTransaction(txHash common.Hash) (*transaction.StoredTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.stored[txHash]
+	if !ok {
+		return nil, transaction.ErrUnknownTransaction
+	}
+	return stored, nil
+}
+
+// UnwrapABIError wraps err with the error of calling req and the reason of
+// the revert, formatted like the transaction service of bee does.
+func (s *ChainTransactions) UnwrapABIError(ctx context.Context, req *transaction.TxRequest, err error, abiErrors map[string]abi.Error) error {
+	if err == nil {
+		return nil
+	}
+	_, cErr := s.Call(ctx, req)
+	if cErr == nil {
+		return err
+	}
+	err = fmt.Errorf("%w: %s", err, cErr) //nolint:errorlint
+	var dataErr rpc.DataError
+	if !errors.As(cErr, &dataErr) {
+		return err
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err
+	}
+	buf := common.FromHex(data)
+	if reason, uErr := abi.UnpackRevert(buf); uErr == nil {
+		return fmt.Errorf("%w: %s", err, reason)
+	}
+	for _, abiError := range abiErrors {
+		if len(buf) < 4 || !bytes.Equal(buf[:4], abiError.ID[:4]) {
+			continue
+		}
+		values, uErr := abiError.Inputs.Unpack(buf[4:])
+		if uErr != nil {
+			continue
+		}
+		params := make([]string, len(values))
+		for i, input := range abiError.Inputs {
+			if input.Name == "" {
+				input.Name = fmt.Sprintf("arg%d", i)
+			}
+			params[i] = fmt.Sprintf("%s=%v", input.Name, values[i])
+		}
+		return fmt.Errorf("%w: %s(%s)", err, abiError.Name, strings.Join(params, ","))
+	}
+	return err
+}
+
+// dataContractCode returns the creation code of DataContract: the exported
+// compiler output if there is one, otherwise an assembled equivalent of
+// sendDataToTarget and sendDataToTargets.
+func dataContractCode(tb testing.TB, contractABI abi.ABI) []byte {
+	tb.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "contract", bytecodeFile)); err == nil {
+		return common.FromHex(strings.TrimSpace(string(data)))
+	}
+
+	code := assemble(tb, dataContractAsm(contractABI))
+	// copy the runtime code that follows these 12 bytes and return it
+	creation := []byte{
+		0x61, byte(len(code) >> 8), byte(len(code)), // PUSH2 len
+		0x80,       // DUP1
+		0x60, 0x0c, // PUSH1 12
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}
+	return append(creation, code...)
+}
+
+func assemble(tb testing.TB, source string) []byte {
+	tb.Helper()
+	c := asm.NewCompiler(false)
+	c.Feed(asm.Lex([]byte(source), false))
+	out, errs := c.Compile()
+	if len(errs) != 0 {
+		tb.Fatalf("assemble data contract: %v", errors.Join(errs...))
+	}
+	code, err := hex.DecodeString(out)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return code
+}
+
+// dataContractAsm is DataContract in EVM assembly. Both methods emit
+// DataSentToTarget(msg.sender, target, owner, actref, topic) per target and
+// revert with the messages of the Solidity contract.
+func dataContractAsm(contractABI abi.ABI) string {
+	return fmt.Sprintf(`
+	PUSH 0x00
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	DUP1
+	PUSH 0x%x
+	EQ
+	JUMPI @single
+	DUP1
+	PUSH 0x%x
+	EQ
+	JUMPI @batch
+	PUSH 0x00
+	DUP1
+	REVERT
+single:
+	POP
+	PUSH 0x04 ;; the target
+	PUSH 0x01
+	JUMP @emit
+batch:
+	POP
+	PUSH 0x04
+	CALLDATALOAD
+	PUSH 0x04
+	ADD ;; the targets array
+	DUP1
+	CALLDATALOAD
+	DUP1
+	ISZERO
+	JUMPI @empty
+	SWAP1
+	PUSH 0x20
+	ADD
+	SWAP1
+	JUMP @emit
+emit: ;; [ptr, n] the first target and the count
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 0x00
+	MSTORE ;; owner
+	PUSH 0x44
+	CALLDATALOAD
+	PUSH 0x20
+	MSTORE ;; actref
+	PUSH 0x60
+	PUSH 0x40
+	MSTORE ;; offset of topic
+	PUSH 0x64
+	CALLDATALOAD
+	PUSH 0x04
+	ADD
+	DUP1
+	CALLDATALOAD
+	PUSH 0x1f
+	ADD
+	PUSH 0x05
+	SHR
+	PUSH 0x05
+	SHL
+	PUSH 0x20
+	ADD ;; [ptr, n, src, size] topic length and padded bytes
+	DUP1
+	SWAP2
+	PUSH 0x60
+	CALLDATACOPY
+	PUSH 0x60
+	ADD ;; [ptr, n, datalen]
+loop:
+	DUP2
+	ISZERO
+	JUMPI @done
+	DUP3
+	CALLDATALOAD
+	DUP1
+	ISZERO
+	JUMPI @zero
+	CALLER
+	PUSH 0x%x
+	DUP4
+	PUSH 0x00
+	LOG3
+	SWAP2
+	PUSH 0x20
+	ADD
+	SWAP2
+	SWAP1
+	PUSH 0x01
+	SWAP1
+	SUB
+	SWAP1
+	JUMP @loop
+done:
+	STOP
+zero:
+%s
+empty:
+%s`,
+		contractABI.Methods["sendDataToTarget"].ID,
+		contractABI.Methods["sendDataToTargets"].ID,
+		contractABI.Events["DataSentToTarget"].ID.Bytes(),
+		revertAsm("DataContract: target cannot be zero address"),
+		revertAsm("DataContract: no targets"),
+	)
+}
+
+// revertAsm reverts with Error(message), message is at most 64 bytes.
+func revertAsm(message string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\tPUSH 0x08c379a0\n\tPUSH 0xe0\n\tSHL\n\tPUSH 0x00\n\tMSTORE\n")
+	fmt.Fprintf(&b, "\tPUSH 0x20\n\tPUSH 0x04\n\tMSTORE\n")
+	fmt.Fprintf(&b, "\tPUSH 0x%x\n\tPUSH 0x24\n\tMSTORE\n", len(message))
+	for i := 0; i < len(message); i += 32 {
+		chunk := message[i:min(i+32, len(message))]
+		fmt.Fprintf(&b, "\tPUSH %q\n", chunk)
+		if len(chunk) < 32 {
+			fmt.Fprintf(&b, "\tPUSH 0x%x\n\tSHL\n", (32-len(chunk))*8)
+		}
+		fmt.Fprintf(&b, "\tPUSH 0x%x\n\tMSTORE\n", 0x44+i)
+	}
+	fmt.Fprintf(&b, "\tPUSH 0x%x\n\tPUSH 0x00\n\tREVERT\n", 0x44+(len(message)+31)/32*32)
+	return b.String()
+}
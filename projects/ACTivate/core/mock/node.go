@@ -0,0 +1,381 @@
+// Package mock provides an in-memory stand-in for the bee-lite node, so that
+// uploads, downloads, grantee lists and stamps can be exercised without a
+// Swarm network. Chain is a simulated chain with the data contract deployed,
+// for the chain side of the node.
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/file/redundancy"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/storage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+	transactionmock "github.com/ethersphere/bee/v2/pkg/transaction/mock"
+)
+
+const (
+	bucketDepth      = 16
+	defaultPeerCount = 8
+)
+
+var (
+	ErrAccessDenied = errors.New("access denied")
+	ErrUnknownBatch = errors.New("batch is not usable")
+	ErrNotPublisher = errors.New("the grantee list belongs to another publisher")
+	ErrNothingToDo  = errors.New("nothing to add or remove")
+	ErrNodeStopped  = errors.New("the node is stopped")
+)
+
+// Network is the content and the access control lists shared by the nodes
+// created from it, like the chunks of a Swarm network.
+type Network struct {
+	mu        sync.Mutex
+	next      uint64
+	content   map[string]content
+	lists     map[string]granteeList
+	histories map[string]history
+}
+
+type content struct {
+	data []byte
+	// publisher and history are set for uploads protected by an access
+	// control list.
+	publisher string
+	history   string
+}
+
+type granteeList struct {
+	publisher string
+	grantees  []string
+}
+
+// history is a version of an access control list. A history made from
+// another one keeps access to the content of its parent.
+type history struct {
+	publisher string
+	parent    string
+	grantees  []string
+}
+
+func NewNetwork() *Network {
+	return &Network{
+		content:   map[string]content{},
+		lists:     map[string]granteeList{},
+		histories: map[string]history{},
+	}
+}
+
+// newAddress returns a reference that was never returned before.
+func (n *Network) newAddress(data []byte) swarm.Address {
+	n.next++
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, n.next)
+	h.Write(data)
+	return swarm.NewAddress(h.Sum(nil))
+}
+
+// granted reports whether grantee can read c with the history h: h must be
+// the history of the upload or one made from it, and list grantee. Revoked
+// grantees keep access through the histories made before.
+func (n *Network) granted(c content, h string, grantee string) bool {
+	if grantee != c.publisher && !slices.Contains(n.histories[h].grantees, grantee) {
+		return false
+	}
+	for h != "" {
+		hist, ok := n.histories[h]
+		if !ok || hist.publisher != c.publisher {
+			return false
+		}
+		if h == c.history {
+			return true
+		}
+		h = hist.parent
+	}
+	return false
+}
+
+// Node is a bee-lite node of a Network. Its settable fields are read on
+// every call.
+type Node struct {
+	network *Network
+	key     *ecdsa.PrivateKey
+
+	mu      sync.Mutex
+	batches []*postage.StampIssuer
+	stopped bool
+
+	Mode         api.BeeNodeMode
+	Peers        int
+	Chequebook   *big.Int
+	Transactions transaction.Service
+}
+
+// NewNode returns a light node of network with key, or with a new key if key
+// is nil.
+func (n *Network) NewNode(key *ecdsa.PrivateKey) (*Node, error) {
+	if key == nil {
+		var err error
+		if key, err = crypto.GenerateSecp256k1Key(); err != nil {
+			return nil, err
+		}
+	}
+	return &Node{
+		network:      n,
+		key:          key,
+		Mode:         api.LightMode,
+		Peers:        defaultPeerCount,
+		Chequebook:   big.NewInt(0),
+		Transactions: transactionmock.New(),
+	}, nil
+}
+
+// PublicKeyHex is the public key of the node as listed in grantee lists.
+func (n *Node) PublicKeyHex() string {
+	return publicKeyHex(&n.key.PublicKey)
+}
+
+func publicKeyHex(key *ecdsa.PublicKey) string {
+	return hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(key))
+}
+
+// AddBatch makes a usable batch of the given depth and returns its ID.
+func (n *Node) AddBatch(label string, depth uint8) []byte {
+	_, id, _ := n.BuyStamp(big.NewInt(1), uint64(depth), label, false)
+	return id
+}
+
+func (n *Node) checkBatch(batchHex string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return ErrNodeStopped
+	}
+	for _, b := range n.batches {
+		if hex.EncodeToString(b.ID()) == batchHex {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownBatch, batchHex)
+}
+
+func (n *Node) AddFileBzz(ctx context.Context, batchHex, filename, contentType string, act bool, historyAddress swarm.Address, encrypt bool, rLevel redundancy.Level, reader io.Reader) (reference, newHistoryAddress swarm.Address, err error) {
+	if err := n.checkBatch(batchHex); err != nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, err
+	}
+
+	net := n.network
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	c := content{data: data}
+	if act {
+		c.publisher = n.PublicKeyHex()
+		if historyAddress.IsZero() {
+			historyAddress = net.newAddress(nil)
+			net.histories[historyAddress.String()] = history{publisher: c.publisher}
+		} else if h, ok := net.histories[historyAddress.String()]; !ok || h.publisher != c.publisher {
+			return swarm.ZeroAddress, swarm.ZeroAddress, fmt.Errorf("history %s: %w", historyAddress, storage.ErrNotFound)
+		}
+		c.history = historyAddress.String()
+	}
+	reference = net.newAddress(data)
+	net.content[reference.String()] = c
+	if !act {
+		return reference, swarm.ZeroAddress, nil
+	}
+	return reference, historyAddress, nil
+}
+
+// GetBytes returns content protected by an access control list only to its
+// publisher and to the grantees of historyAddress.
+func (n *Node) GetBytes(ctx context.Context, reference swarm.Address, publisher *ecdsa.PublicKey, historyAddress *swarm.Address, timestamp *int64) (io.Reader, error) {
+	net := n.network
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	c, ok := net.content[reference.String()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if c.publisher != "" {
+		if publisher == nil || historyAddress == nil || publicKeyHex(publisher) != c.publisher {
+			return nil, ErrAccessDenied
+		}
+		if !net.granted(c, historyAddress.String(), n.PublicKeyHex()) {
+			return nil, ErrAccessDenied
+		}
+	}
+	return bytes.NewReader(c.data), nil
+}
+
+func (n *Node) GetGranteeList(ctx context.Context, encryptedglRef swarm.Address, cache bool) ([]string, error) {
+	net := n.network
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	l, ok := net.lists[encryptedglRef.String()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if l.publisher != n.PublicKeyHex() {
+		return nil, ErrNotPublisher
+	}
+	return slices.Clone(l.grantees), nil
+}
+
+func (n *Node) CreateGrantees(ctx context.Context, batchHex string, historyAddress swarm.Address, granteeList []string) (swarm.Address, swarm.Address, error) {
+	if granteeList == nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, errors.New("nothing to create")
+	}
+	return n.updateGrantees(batchHex, swarm.ZeroAddress, historyAddress, granteeList, nil)
+}
+
+func (n *Node) AddRevokeGrantees(ctx context.Context, batchHex string, granteesAddress, historyAddress swarm.Address, addlist, revokelist []string) (swarm.Address, swarm.Address, error) {
+	if addlist == nil && revokelist == nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, ErrNothingToDo
+	}
+	return n.updateGrantees(batchHex, granteesAddress, historyAddress, addlist, revokelist)
+}
+
+// updateGrantees stores a new grantee list, the one of granteesAddress with
+// add and without revoke, and a new history made from historyAddress.
+func (n *Node) updateGrantees(batchHex string, granteesAddress, historyAddress swarm.Address, add, revoke []string) (swarm.Address, swarm.Address, error) {
+	if err := n.checkBatch(batchHex); err != nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, err
+	}
+	add, err := parseKeys(add)
+	if err != nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, err
+	}
+	revoke, err = parseKeys(revoke)
+	if err != nil {
+		return swarm.ZeroAddress, swarm.ZeroAddress, err
+	}
+
+	net := n.network
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	publisher := n.PublicKeyHex()
+	var grantees []string
+	if !granteesAddress.IsZero() {
+		l, ok := net.lists[granteesAddress.String()]
+		if !ok {
+			return swarm.ZeroAddress, swarm.ZeroAddress, fmt.Errorf("grantee list %s: %w", granteesAddress, storage.ErrNotFound)
+		}
+		if l.publisher != publisher {
+			return swarm.ZeroAddress, swarm.ZeroAddress, ErrNotPublisher
+		}
+		grantees = slices.Clone(l.grantees)
+	}
+	if !historyAddress.IsZero() {
+		if h, ok := net.histories[historyAddress.String()]; !ok || h.publisher != publisher {
+			return swarm.ZeroAddress, swarm.ZeroAddress, fmt.Errorf("history %s: %w", historyAddress, storage.ErrNotFound)
+		}
+	}
+	grantees = slices.DeleteFunc(grantees, func(g string) bool { return slices.Contains(revoke, g) })
+	for _, g := range add {
+		if !slices.Contains(grantees, g) {
+			grantees = append(grantees, g)
+		}
+	}
+
+	eglRef := net.newAddress(nil)
+	net.lists[eglRef.String()] = granteeList{publisher: publisher, grantees: grantees}
+	historyRef := net.newAddress(nil)
+	net.histories[historyRef.String()] = history{
+		publisher: publisher,
+		parent:    historyAddress.String(),
+		grantees:  slices.Clone(grantees),
+	}
+	return eglRef, historyRef, nil
+}
+
+// parseKeys returns the keys in the compressed form of GetGranteeList.
+func parseKeys(keys []string) ([]string, error) {
+	parsed := make([]string, 0, len(keys))
+	for _, k := range keys {
+		b, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode grantee: %w", err)
+		}
+		pub, err := ethcrypto.UnmarshalPubkey(b)
+		if err != nil {
+			if pub, err = ethcrypto.DecompressPubkey(b); err != nil {
+				return nil, fmt.Errorf("failed to parse grantee public key: %w", err)
+			}
+		}
+		parsed = append(parsed, publicKeyHex(pub))
+	}
+	return parsed, nil
+}
+
+func (n *Node) GetUsableBatches() []*postage.StampIssuer {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.batches)
+}
+
+// BuyStamp adds a usable batch at once, the transaction hash is made up.
+func (n *Node) BuyStamp(amount *big.Int, depth uint64, label string, immutable bool) (common.Hash, []byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return common.Hash{}, nil, ErrNodeStopped
+	}
+	n.network.mu.Lock()
+	id := n.network.newAddress([]byte(label)).Bytes()
+	n.network.mu.Unlock()
+	issuer := postage.NewStampIssuer(label, "", id, amount, uint8(depth), bucketDepth, 0, immutable)
+	n.batches = append(n.batches, issuer)
+	return common.BytesToHash(id), id, nil
+}
+
+func (n *Node) ChequebookBalance() (*big.Int, error) {
+	return n.Chequebook, nil
+}
+
+func (n *Node) OverlayEthAddress() common.Address {
+	return ethcrypto.PubkeyToAddress(n.key.PublicKey)
+}
+
+func (n *Node) PublicKey() *ecdsa.PublicKey {
+	return &n.key.PublicKey
+}
+
+func (n *Node) BeeNodeMode() api.BeeNodeMode {
+	return n.Mode
+}
+
+func (n *Node) ConnectedPeerCount() int {
+	return n.Peers
+}
+
+func (n *Node) TransactionService() transaction.Service {
+	return n.Transactions
+}
+
+// Shutdown stops the node.
+func (n *Node) Shutdown() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stopped = true
+	return nil
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"activate/contract/deployments"
+)
+
+const (
+	TestnetChainID   = 11155111
+	TestnetNetworkID = uint64(10)
+	MainnetChainID   = 100
+	MainnetNetworkID = uint64(1)
+	ChiadoChainID    = 10200
+	LocalDevChainID  = 1337
+
+	GnosisNetwork   = "gnosis"
+	SepoliaNetwork  = "sepolia"
+	ChiadoNetwork   = "chiado"
+	LocalDevNetwork = "local"
+	DefaultNetwork  = GnosisNetwork
+
+	DefaultRPC     = "wss://gnosis-mainnet.g.alchemy.com/v2/YtM4LIorMJrGNRWkvAOFWSKTDzhNsCMz"
+	DefaultTestRPC = "https://eth-sepolia.g.alchemy.com/v2/atcICv4EFi9hXKew1D4LvnH36cm5-96S"
+
+	// DeploymentsDir holds manifests in the data dir that override the
+	// bundled deployments.
+	DeploymentsDir = "deployments"
+)
+
+var (
+	ErrLightModeUnsupported = errors.New("bee has no contracts on this chain, the node only runs in ultra-light mode")
+
+	MainnetBootnodes = []string{
+		"/dnsaddr/mainnet.ethswarm.org",
+	}
+
+	TestnetBootnodes = []string{
+		"/dnsaddr/testnet.ethswarm.org",
+	}
+)
+
+// NetworkProfile describes the chain and Swarm network a node runs against,
+// together with the data contract deployment used for sharing.
+//
+// UltraLightOnly marks the chains bee has no postage and chequebook contracts
+// for. A light node cannot start there, the node runs in ultra-light mode and
+// the chain only carries the data contract.
+type NetworkProfile struct {
+	Name           string
+	DisplayName    string
+	ChainID        int64
+	SwarmNetworkID uint64
+	Mainnet        bool
+	UltraLightOnly bool
+	Bootnodes      []string
+	RPCEndpoint    string
+	ExplorerURL    string
+	BlockTime      time.Duration
+	Deployment     *deployments.Manifest
+}
+
+// NetworkProfiles are the networks a node can run on.
+var NetworkProfiles = []*NetworkProfile{
+	{
+		Name:           GnosisNetwork,
+		DisplayName:    "Gnosis Chain (mainnet)",
+		ChainID:        MainnetChainID,
+		SwarmNetworkID: MainnetNetworkID,
+		Mainnet:        true,
+		Bootnodes:      MainnetBootnodes,
+		RPCEndpoint:    DefaultRPC,
+		ExplorerURL:    "https://gnosisscan.io",
+		BlockTime:      5 * time.Second,
+	},
+	{
+		Name:           SepoliaNetwork,
+		DisplayName:    "Sepolia (testnet)",
+		ChainID:        TestnetChainID,
+		SwarmNetworkID: TestnetNetworkID,
+		Mainnet:        false,
+		Bootnodes:      TestnetBootnodes,
+		RPCEndpoint:    DefaultTestRPC,
+		ExplorerURL:    "https://sepolia.etherscan.io",
+		BlockTime:      12 * time.Second,
+	},
+	{
+		Name:           ChiadoNetwork,
+		DisplayName:    "Chiado (Gnosis testnet, no light mode)",
+		ChainID:        ChiadoChainID,
+		SwarmNetworkID: TestnetNetworkID,
+		Mainnet:        false,
+		UltraLightOnly: true,
+		Bootnodes:      TestnetBootnodes,
+		RPCEndpoint:    "https://rpc.chiadochain.net",
+		ExplorerURL:    "https://gnosis-chiado.blockscout.com",
+		BlockTime:      5 * time.Second,
+	},
+	{
+		Name:           LocalDevNetwork,
+		DisplayName:    "Local dev chain (no light mode)",
+		ChainID:        LocalDevChainID,
+		SwarmNetworkID: TestnetNetworkID,
+		Mainnet:        false,
+		UltraLightOnly: true,
+		Bootnodes:      []string{},
+		RPCEndpoint:    "http://127.0.0.1:8545",
+		BlockTime:      time.Second,
+	},
+}
+
+// FindNetworkProfile returns the profile with the given name or nil.
+func FindNetworkProfile(name string) *NetworkProfile {
+	for _, p := range NetworkProfiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// HasDataContract reports whether the profile has a data contract deployment.
+func (p *NetworkProfile) HasDataContract() bool {
+	return p.Deployment != nil
+}
+
+// ApplyDeployments links every profile to the latest deployment on its chain.
+// Manifests in overrides take precedence over the defaults.
+func ApplyDeployments(defaults, overrides []*deployments.Manifest) {
+	for _, p := range NetworkProfiles {
+		if m := deployments.Latest(overrides, p.ChainID); m != nil {
+			p.Deployment = m
+		} else {
+			p.Deployment = deployments.Latest(defaults, p.ChainID)
+		}
+	}
+}
+
+// LoadDeployments applies the bundled deployments and the overrides found in
+// the data dir, which is skipped when empty. The profiles are linked even if
+// one of the sources fails to load.
+func LoadDeployments(dataDir string) error {
+	defaults, err := deployments.Embedded()
+	if err != nil {
+		err = fmt.Errorf("bundled deployments: %w", err)
+	}
+
+	var overrides []*deployments.Manifest
+	if dataDir != "" {
+		dir := filepath.Join(dataDir, DeploymentsDir)
+		var dirErr error
+		overrides, dirErr = deployments.LoadDir(dir)
+		if dirErr != nil {
+			err = errors.Join(err, fmt.Errorf("deployments from %s: %w", dir, dirErr))
+		}
+	}
+
+	ApplyDeployments(defaults, overrides)
+	return err
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"math/big"
+
+	beelite "github.com/Solar-Punk-Ltd/bee-lite"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/file/redundancy"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+const (
+	DefaultWelcomeMessage = "Welcome from ACTivate!"
+	InfoLogLevel          = "3"
+	// CacheCapacity is the local store cache size in chunks.
+	CacheCapacity = 32 * 1024 * 1024
+	// NodePasswordSecret is the vault entry holding the keystore password.
+	NodePasswordSecret = "nodePassword"
+)
+
+// BeeNode is the part of the bee-lite API the app uses. *beelite.Beelite
+// implements it, the in-memory node of package mock stands in for it in
+// tests.
+type BeeNode interface {
+	AddFileBzz(ctx context.Context, batchHex, filename, contentType string, act bool, historyAddress swarm.Address, encrypt bool, rLevel redundancy.Level, reader io.Reader) (reference, newHistoryAddress swarm.Address, err error)
+	GetBytes(ctx context.Context, reference swarm.Address, publisher *ecdsa.PublicKey, historyAddress *swarm.Address, timestamp *int64) (io.Reader, error)
+	GetGranteeList(ctx context.Context, encryptedglRef swarm.Address, cache bool) ([]string, error)
+	CreateGrantees(ctx context.Context, batchHex string, historyAddress swarm.Address, granteeList []string) (swarm.Address, swarm.Address, error)
+	AddRevokeGrantees(ctx context.Context, batchHex string, granteesAddress, historyAddress swarm.Address, addlist, revokelist []string) (swarm.Address, swarm.Address, error)
+	GetUsableBatches() []*postage.StampIssuer
+	BuyStamp(amount *big.Int, depth uint64, label string, immutable bool) (common.Hash, []byte, error)
+	ChequebookBalance() (*big.Int, error)
+	OverlayEthAddress() common.Address
+	PublicKey() *ecdsa.PublicKey
+	BeeNodeMode() api.BeeNodeMode
+	ConnectedPeerCount() int
+	TransactionService() transaction.Service
+}
+
+var _ BeeNode = (*beelite.Beelite)(nil)
+
+// startBeeLite is the default NewNode of a Service.
+func startBeeLite(opts *NodeOptions, password string) (BeeNode, error) {
+	bl, err := beelite.Start(opts.LiteOptions(), password, InfoLogLevel)
+	if err != nil {
+		return nil, err
+	}
+	return bl, nil
+}
+
+// NodeOptions are the settings a node is started with. The node runs in
+// light mode when swap is enabled and in ultra-light mode otherwise.
+type NodeOptions struct {
+	DataDir        string
+	WelcomeMessage string
+	NATAddress     string
+	RPCEndpoint    string
+	SwapEnable     bool
+	Network        *NetworkProfile
+
+	// EventsRPCEndpoints are tried first for the data contract, before the
+	// node RPC and, with RPCFallback, the built-in provider of the network.
+	EventsRPCEndpoints string
+	RPCFallback        bool
+}
+
+// NodeOptionsFromPreferences returns the options saved by the last start,
+// light mode needs a saved RPC endpoint and a network that supports it.
+func NodeOptionsFromPreferences(prefs Preferences, dataDir string) *NodeOptions {
+	o := &NodeOptions{
+		DataDir:        dataDir,
+		WelcomeMessage: prefs.String(WelcomeMessagePrefKey),
+		NATAddress:     prefs.String(NatAddressPrefKey),
+		RPCEndpoint:    prefs.String(RPCEndpointPrefKey),
+		SwapEnable:     prefs.BoolWithFallback(SwapEnablePrefKey, false),
+		Network:        FindNetworkProfile(prefs.String(NetworkPrefKey)),
+
+		EventsRPCEndpoints: prefs.String(EventsRPCPrefKey),
+		RPCFallback:        prefs.BoolWithFallback(RPCFallbackPrefKey, true),
+	}
+	if o.WelcomeMessage == "" {
+		o.WelcomeMessage = DefaultWelcomeMessage
+	}
+	if o.Network == nil {
+		o.Network = FindNetworkProfile(DefaultNetwork)
+	}
+	if o.RPCEndpoint == "" || o.Network.UltraLightOnly {
+		o.SwapEnable = false
+	}
+	return o
+}
+
+// Save stores the options for the next start.
+func (o *NodeOptions) Save(prefs Preferences) {
+	prefs.SetString(WelcomeMessagePrefKey, o.WelcomeMessage)
+	prefs.SetBool(SwapEnablePrefKey, o.SwapEnable)
+	prefs.SetString(NatAddressPrefKey, o.NATAddress)
+	prefs.SetString(RPCEndpointPrefKey, o.RPCEndpoint)
+	prefs.SetString(NetworkPrefKey, o.Network.Name)
+	prefs.SetString(EventsRPCPrefKey, o.EventsRPCEndpoints)
+	prefs.SetBool(RPCFallbackPrefKey, o.RPCFallback)
+}
+
+// ContractRPCEndpoints lists the endpoints the data contract may use, in
+// order of preference: the dedicated events RPCs, the endpoint of the node
+// and, if allowed, the built-in provider of the network.
+func (o *NodeOptions) ContractRPCEndpoints() []string {
+	endpoints := SplitRPCEndpoints(o.EventsRPCEndpoints)
+	if o.SwapEnable && o.RPCEndpoint != "" {
+		endpoints = append(endpoints, o.RPCEndpoint)
+	}
+	if o.RPCFallback {
+		endpoints = append(endpoints, o.Network.RPCEndpoint)
+	}
+	return endpoints
+}
+
+// LiteOptions returns the bee-lite configuration of the node.
+func (o *NodeOptions) LiteOptions() *beelite.LiteOptions {
+	return &beelite.LiteOptions{
+		FullNodeMode:             false,
+		BootnodeMode:             false,
+		Bootnodes:                o.Network.Bootnodes,
+		DataDir:                  o.DataDir,
+		WelcomeMessage:           o.WelcomeMessage,
+		BlockchainRpcEndpoint:    o.RPCEndpoint,
+		SwapInitialDeposit:       "0",
+		PaymentThreshold:         "100000000",
+		SwapEnable:               o.SwapEnable,
+		ChequebookEnable:         true,
+		UsePostageSnapshot:       false,
+		Mainnet:                  o.Network.Mainnet,
+		NetworkID:                o.Network.SwarmNetworkID,
+		NATAddr:                  o.NATAddress,
+		CacheCapacity:            CacheCapacity,
+		DBOpenFilesLimit:         50,
+		DBWriteBufferSize:        32 * 1024 * 1024,
+		DBBlockCacheCapacity:     32 * 1024 * 1024,
+		DBDisableSeeksCompaction: false,
+		RetrievalCaching:         true,
+	}
+}
+
+// NodeModeError is returned when the node runs in another mode than the one
+// implied by the swap setting, usually because bee fell back to ultra-light
+// mode.
+type NodeModeError struct {
+	Mode       api.BeeNodeMode
+	SwapEnable bool
+}
+
+func (e *NodeModeError) Error() string {
+	if e.SwapEnable {
+		return fmt.Sprintf("swap is enabled but the current node mode is: %s", e.Mode)
+	}
+	return fmt.Sprintf("swap disabled but the current node mode is: %s", e.Mode)
+}
+
+// CheckNodeMode returns a *NodeModeError if mode is not the one implied by
+// swapEnable.
+func CheckNodeMode(mode api.BeeNodeMode, swapEnable bool) error {
+	if (swapEnable && mode != api.LightMode) || (!swapEnable && mode != api.UltraLightMode) {
+		return &NodeModeError{Mode: mode, SwapEnable: swapEnable}
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/v2/pkg/sctx"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const (
+	// maxOutboxEntries bounds the outbox, which is saved as one preference
+	// string. The oldest finished entries are dropped first.
+	maxOutboxEntries = 200
+	// recheckTimeout bounds the wait for the receipt of each entry checked by
+	// RecheckOutbox, the entry stays pending after it.
+	recheckTimeout = 10 * time.Minute
+)
+
+// OutboxStatus is the state of a sent share transaction.
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxConfirmed OutboxStatus = "confirmed"
+	OutboxReverted  OutboxStatus = "reverted"
+	OutboxCancelled OutboxStatus = "cancelled"
+	OutboxFailed    OutboxStatus = "failed"
+)
+
+// OutboxEntry records a SendDataToTarget transaction, or a SendDataToTargets
+// transaction with Targets instead of Target. Pending entries are checked
+// again by RecheckOutbox when the app restarts.
+//
+// TxHash identifies the entry even when a cancellation sent by Cancel is mined
+// instead, MinedTx is then the hash of the cancellation. ResentAs is the entry
+// of the transaction SpeedUp sent in place of this one.
+type OutboxEntry struct {
+	Target      string
+	Targets     []string `json:",omitempty"`
+	Reference   string
+	Topic       string
+	TxHash      string
+	Cancels     []string `json:",omitempty"`
+	ResentAs    string   `json:",omitempty"`
+	MinedTx     string   `json:",omitempty"`
+	BlockNumber uint64
+	GasUsed     uint64
+	Status      OutboxStatus
+	Error       string `json:",omitempty"`
+	Timestamp   time.Time
+}
+
+// Recipients returns the targets of the transaction.
+func (e OutboxEntry) Recipients() []string {
+	if len(e.Targets) != 0 {
+		return e.Targets
+	}
+	return []string{e.Target}
+}
+
+// Hashes returns the hashes of the transaction and of its cancellations, the
+// last one sent last.
+func (e OutboxEntry) Hashes() []common.Hash {
+	hashes := []common.Hash{common.HexToHash(e.TxHash)}
+	for _, h := range e.Cancels {
+		hashes = append(hashes, common.HexToHash(h))
+	}
+	return hashes
+}
+
+// Outbox returns the sent share transactions, oldest first.
+func (s *Service) Outbox() ([]OutboxEntry, error) {
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+	return s.readOutbox()
+}
+
+// OutboxEntry returns the outbox entry of txHash.
+func (s *Service) OutboxEntry(txHash common.Hash) (OutboxEntry, error) {
+	entries, err := s.Outbox()
+	if err != nil {
+		return OutboxEntry{}, err
+	}
+	for _, e := range entries {
+		if e.TxHash == txHash.Hex() {
+			return e, nil
+		}
+	}
+	return OutboxEntry{}, ErrNotInOutbox
+}
+
+func (s *Service) readOutbox() ([]OutboxEntry, error) {
+	entries := []OutboxEntry{}
+	if v := s.Prefs.String(OutboxPrefKey); v != "" {
+		if err := json.Unmarshal([]byte(v), &entries); err != nil {
+			return nil, fmt.Errorf("read outbox: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+// updateOutbox changes the entry of txHash with update, adding the entry if
+// there is none. The changed entry is passed to OnOutboxUpdate.
+func (s *Service) updateOutbox(txHash common.Hash, update func(e *OutboxEntry)) {
+	e, ok := s.saveOutboxEntry(txHash, update)
+	if ok && s.OnOutboxUpdate != nil {
+		s.OnOutboxUpdate(e)
+	}
+}
+
+func (s *Service) saveOutboxEntry(txHash common.Hash, update func(e *OutboxEntry)) (OutboxEntry, bool) {
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+	entries, err := s.readOutbox()
+	if err != nil {
+		s.Logger.Log(err.Error())
+		entries = []OutboxEntry{}
+	}
+	n := -1
+	for i := range entries {
+		if entries[i].TxHash == txHash.Hex() {
+			n = i
+			break
+		}
+	}
+	if n < 0 {
+		entries = append(entries, OutboxEntry{TxHash: txHash.Hex()})
+		n = len(entries) - 1
+	}
+	update(&entries[n])
+	e := entries[n]
+	data, err := json.Marshal(pruneOutbox(entries))
+	if err != nil {
+		s.Logger.Log(fmt.Sprintf("save outbox: %v", err))
+		return OutboxEntry{}, false
+	}
+	s.Prefs.SetString(OutboxPrefKey, string(data))
+	return e, true
+}
+
+// pruneOutbox drops the oldest entries that are no longer pending until at
+// most maxOutboxEntries are left. Pending entries are kept for RecheckOutbox.
+func pruneOutbox(entries []OutboxEntry) []OutboxEntry {
+	drop := len(entries) - maxOutboxEntries
+	if drop <= 0 {
+		return entries
+	}
+	kept := make([]OutboxEntry, 0, maxOutboxEntries)
+	for _, e := range entries {
+		if drop > 0 && e.Status != OutboxPending {
+			drop--
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// SendData sends owner, actRef and topic to target through the data contract
+// and records the transaction in the outbox, first as pending and then with
+// the outcome of its receipt.
+func (s *Service) SendData(ctx context.Context, target common.Address, owner, actRef []byte, topic string) (*types.Receipt, error) {
+	return s.sendData(ctx, []common.Address{target}, owner, actRef, topic)
+}
+
+// SendDataToTargets is SendData to several targets in one transaction. It
+// returns ErrBatchUnsupported if the deployed contract cannot do it.
+func (s *Service) SendDataToTargets(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (*types.Receipt, error) {
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	return s.sendData(ctx, targets, owner, actRef, topic)
+}
+
+func (s *Service) sendData(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (*types.Receipt, error) {
+	txHash, err := s.submitData(ctx, targets, owner, actRef, topic)
+	if err != nil {
+		return nil, err
+	}
+	return s.track(ctx, txHash)
+}
+
+// SubmitData sends owner, actRef and topic to the targets like SendData or
+// SendDataToTargets, but returns once the transaction is sent. The outcome
+// is tracked in the background and reported to OnOutboxUpdate.
+func (s *Service) SubmitData(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (common.Hash, error) {
+	if len(targets) == 0 {
+		return common.Hash{}, ErrNoTargets
+	}
+	txHash, err := s.submitData(ctx, targets, owner, actRef, topic)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	s.trackInBackground(txHash)
+	return txHash, nil
+}
+
+// SubmitShares sends ref to the targets like SendShares without waiting for
+// the receipts, see SubmitData.
+func (s *Service) SubmitShares(ctx context.Context, targets []common.Address, ref swarm.Address) ([]common.Hash, error) {
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+	owner, actRef, topic, err := s.shareData(ref)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := s.SubmitData(ctx, targets, owner, actRef, topic)
+	if err == nil {
+		return []common.Hash{txHash}, nil
+	}
+	if !errors.Is(err, ErrBatchUnsupported) {
+		return nil, err
+	}
+	s.Logger.Log(fmt.Sprintf("Sending to %d targets one by one: %v", len(targets), err))
+	hashes := make([]common.Hash, 0, len(targets))
+	for _, target := range targets {
+		txHash, err := s.SubmitData(ctx, []common.Address{target}, owner, actRef, topic)
+		if err != nil {
+			return hashes, fmt.Errorf("send to %s: %w", target.Hex(), err)
+		}
+		hashes = append(hashes, txHash)
+	}
+	return hashes, nil
+}
+
+func (s *Service) submitData(ctx context.Context, targets []common.Address, owner, actRef []byte, topic string) (common.Hash, error) {
+	node := s.Node()
+	if node == nil {
+		return common.Hash{}, ErrNodeNotStarted
+	}
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var txHash common.Hash
+	if len(targets) == 1 {
+		txHash, err = c.contract.SubmitDataToTarget(ctx, targets[0], owner, actRef, topic)
+	} else {
+		// the gas of a batch grows with the targets, past the fixed limit
+		var estimate *FeeEstimate
+		if estimate, err = c.contract.EstimateFee(ctx, c.client, targets, owner, actRef, topic, 0); err != nil {
+			return common.Hash{}, err
+		}
+		ctx = sctx.SetGasLimit(ctx, estimate.GasLimit)
+		txHash, err = c.contract.SubmitDataToTargets(ctx, targets, owner, actRef, topic)
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	_, reference, _ := SplitShareTopic(topic)
+	s.updateOutbox(txHash, func(e *OutboxEntry) {
+		if len(targets) == 1 {
+			e.Target = targets[0].Hex()
+		} else {
+			for _, t := range targets {
+				e.Targets = append(e.Targets, t.Hex())
+			}
+		}
+		e.Reference = reference
+		e.Topic = topic
+		e.Status = OutboxPending
+		e.Timestamp = time.Now()
+	})
+	return txHash, nil
+}
+
+// RecheckOutbox waits for the receipts of the pending outbox entries, left
+// over when the app stopped before they were mined. The entries are tracked
+// in parallel, each for at most recheckTimeout.
+func (s *Service) RecheckOutbox(ctx context.Context) error {
+	node := s.Node()
+	if node == nil {
+		return ErrNodeNotStarted
+	}
+	entries, err := s.Outbox()
+	if err != nil {
+		return err
+	}
+	var pending []common.Hash
+	for _, e := range entries {
+		if e.Status == OutboxPending {
+			pending = append(pending, common.HexToHash(e.TxHash))
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	s.Logger.Log(fmt.Sprintf("Checking %d pending outbox transactions", len(pending)))
+	errs := make([]error, len(pending))
+	var wg sync.WaitGroup
+	for n, txHash := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, recheckTimeout)
+			defer cancel()
+			if _, err := s.track(ctx, txHash); err != nil {
+				errs[n] = fmt.Errorf("%s: %w", txHash.Hex(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
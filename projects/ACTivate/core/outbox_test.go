@@ -0,0 +1,29 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPruneOutbox(t *testing.T) {
+	entries := make([]OutboxEntry, maxOutboxEntries+3)
+	for n := range entries {
+		entries[n] = OutboxEntry{TxHash: fmt.Sprint(n), Status: OutboxConfirmed}
+	}
+	entries[0].Status = OutboxPending
+	entries[2].Status = OutboxPending
+
+	kept := pruneOutbox(entries)
+	if len(kept) != maxOutboxEntries {
+		t.Fatalf("kept %d entries, want %d", len(kept), maxOutboxEntries)
+	}
+	// the pending entries stay, the three oldest finished ones go
+	for n, want := range []string{"0", "2", "5"} {
+		if kept[n].TxHash != want {
+			t.Errorf("entry %d is %s, want %s", n, kept[n].TxHash, want)
+		}
+	}
+	if got := pruneOutbox(entries[:10]); len(got) != 10 {
+		t.Errorf("pruned a short outbox to %d entries", len(got))
+	}
+}
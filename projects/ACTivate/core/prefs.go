@@ -0,0 +1,234 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+const (
+	// AppID is the Fyne app ID, it names the app directory.
+	AppID = "activate.app"
+	// PreferencesFile is the file Fyne keeps the preferences in.
+	PreferencesFile = "preferences.json"
+
+	NetworkPrefKey        = "network"
+	WelcomeMessagePrefKey = "welcomeMessage"
+	SwapEnablePrefKey     = "swapEnable"
+	NatAddressPrefKey     = "natAddress"
+	RPCEndpointPrefKey    = "rpcEndpoint"
+	EventsRPCPrefKey      = "eventsRpcEndpoints"
+	RPCFallbackPrefKey    = "rpcFallback"
+	SelectedStampPrefKey  = "selected_stamp"
+	BatchPrefKey          = "batch"
+	BatchPolicyPrefKey    = "batchPolicy"
+	GroupBatchesPrefKey   = "groupBatches"
+	UploadsPrefKey        = "uploads"
+	OverlayAddrPrefKey    = "overlayAddress"
+	EglrefPrefKey         = "eglref"
+	HistoryRefPrefKey     = "historyRef"
+	LocalAPIPrefKey       = "localApiAddress"
+	OutboxPrefKey         = "outbox"
+
+	// the last share received, as stored by the event listener
+	EventPublicKeyPrefKey = "eventPublicKey"
+	EventRefPrefKey       = "event32ByteHex"
+	EventOwnerPrefKey     = "eventOwner"
+	EventActRefPrefKey    = "eventActRef"
+	EventTopicPrefKey     = "eventTopic"
+)
+
+// PrefKeys lists every preference the core writes, for a wipe to remove.
+var PrefKeys = []string{
+	NetworkPrefKey,
+	WelcomeMessagePrefKey,
+	SwapEnablePrefKey,
+	NatAddressPrefKey,
+	RPCEndpointPrefKey,
+	EventsRPCPrefKey,
+	RPCFallbackPrefKey,
+	SelectedStampPrefKey,
+	BatchPrefKey,
+	BatchPolicyPrefKey,
+	GroupBatchesPrefKey,
+	UploadsPrefKey,
+	OverlayAddrPrefKey,
+	EglrefPrefKey,
+	HistoryRefPrefKey,
+	LocalAPIPrefKey,
+	OutboxPrefKey,
+	EventPublicKeyPrefKey,
+	EventRefPrefKey,
+	EventOwnerPrefKey,
+	EventActRefPrefKey,
+	EventTopicPrefKey,
+}
+
+// Preferences is the subset of fyne.Preferences the core uses, so the GUI can
+// pass the app preferences and other frontends a FilePreferences.
+type Preferences interface {
+	String(key string) string
+	SetString(key string, value string)
+	BoolWithFallback(key string, fallback bool) bool
+	SetBool(key string, value bool)
+	IntWithFallback(key string, fallback int) int
+	SetInt(key string, value int)
+	RemoveValue(key string)
+}
+
+// NopPreferences keeps nothing, for frontends without persistent storage.
+type NopPreferences struct{}
+
+func (NopPreferences) String(string) string                          { return "" }
+func (NopPreferences) SetString(string, string)                      {}
+func (NopPreferences) BoolWithFallback(_ string, fallback bool) bool { return fallback }
+func (NopPreferences) SetBool(string, bool)                          {}
+func (NopPreferences) IntWithFallback(_ string, fallback int) int    { return fallback }
+func (NopPreferences) SetInt(string, int)                            {}
+func (NopPreferences) RemoveValue(string)                            {}
+
+// DefaultAppDir returns the directory Fyne uses for the app storage and the
+// preferences on desktop systems.
+func DefaultAppDir() (string, error) {
+	var root string
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		root = filepath.Join(home, "Library", "Preferences")
+	case "windows":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		root = filepath.Join(home, "AppData", "Roaming")
+	default:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		root = dir
+	}
+	return filepath.Join(root, "fyne", AppID), nil
+}
+
+// FilePreferences reads and writes the preferences file of the GUI. Every
+// change is saved right away. The GUI reloads the file when it changes, but
+// changes made by both at the same moment may be lost.
+type FilePreferences struct {
+	mu     sync.Mutex
+	path   string
+	values map[string]interface{}
+	err    error
+}
+
+// OpenFilePreferences loads the preferences file in dir, a missing file is
+// an empty set of preferences.
+func OpenFilePreferences(dir string) (*FilePreferences, error) {
+	p := &FilePreferences{path: filepath.Join(dir, PreferencesFile), values: map[string]interface{}{}}
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read preferences: %w", err)
+	}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &p.values); err != nil {
+			return nil, fmt.Errorf("decode preferences: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// Err returns the last error saving the preferences.
+func (p *FilePreferences) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *FilePreferences) String(key string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, _ := p.values[key].(string)
+	return s
+}
+
+func (p *FilePreferences) SetString(key string, value string) {
+	p.set(key, value)
+}
+
+func (p *FilePreferences) BoolWithFallback(key string, fallback bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if b, ok := p.values[key].(bool); ok {
+		return b
+	}
+	return fallback
+}
+
+func (p *FilePreferences) SetBool(key string, value bool) {
+	p.set(key, value)
+}
+
+func (p *FilePreferences) IntWithFallback(key string, fallback int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch n := p.values[key].(type) {
+	case int:
+		return n
+	case float64:
+		// JSON numbers load as float64
+		return int(n)
+	}
+	return fallback
+}
+
+func (p *FilePreferences) SetInt(key string, value int) {
+	p.set(key, value)
+}
+
+func (p *FilePreferences) RemoveValue(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.values, key)
+	p.save()
+}
+
+func (p *FilePreferences) set(key string, value interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[key] = value
+	p.save()
+}
+
+// save writes the file through a temporary file, so a reader never sees it
+// half written.
+func (p *FilePreferences) save() {
+	data, err := json.Marshal(p.values)
+	if err != nil {
+		p.err = fmt.Errorf("encode preferences: %w", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		p.err = fmt.Errorf("save preferences: %w", err)
+		return
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		p.err = fmt.Errorf("save preferences: %w", err)
+		return
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		p.err = fmt.Errorf("save preferences: %w", err)
+		return
+	}
+	p.err = nil
+}
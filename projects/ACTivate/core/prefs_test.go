@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"slices"
+	"testing"
+
+	"activate/core/mock"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestPrefKeys goes through the flows that write preferences and checks that
+// a wipe removes every key written.
+func TestPrefKeys(t *testing.T) {
+	ctx := context.Background()
+	c := newSimChain(t)
+	s, node := newSimService(t, c, nil)
+	batch := hex.EncodeToString(node.AddBatch("test", 20))
+	grantee, err := mock.NewNetwork().NewNode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetGroupBatch("uploads", batch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateGroup(ctx, batch, []string{grantee.PublicKeyHex()}); err != nil {
+		t.Fatal(err)
+	}
+	ref := upload(t, s, batch, "shared")
+	owner, actRef, topic, err := s.shareData(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := s.SendData(ctx, common.HexToAddress("0x1000000000000000000000000000000000000001"), owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReceiveShare(*receipt.Logs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	prefs := s.Prefs.(*FilePreferences)
+	prefs.mu.Lock()
+	defer prefs.mu.Unlock()
+	for key := range prefs.values {
+		if !slices.Contains(PrefKeys, key) {
+			t.Errorf("preference %q is written but not in PrefKeys", key)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+// RevertError is a contract call that reverted, with the reason decoded from
+// the revert data.
+type RevertError struct {
+	// Reason is the message of a require or revert, or the custom error
+	// with its arguments.
+	Reason string
+	Err    error
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Reason)
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// explain simulates request with eth_call after err through the transaction
+// service, so that a revert is returned as a *RevertError with the reason
+// the service decodes: the message of a require or revert, or one of the
+// custom errors of the ABI with its arguments. Other errors are returned as
+// they are.
+func (c *datacontract) explain(ctx context.Context, request *transaction.TxRequest, err error) error {
+	if err == nil || c.transactionService == nil {
+		return err
+	}
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return err
+	}
+	// The service wraps err with the error of the call and then with the
+	// reason, if it could decode one.
+	unwrapped := c.transactionService.UnwrapABIError(ctx, request, err, c.dataContractABI.Errors)
+	callErr := errors.Unwrap(unwrapped)
+	if callErr == nil || errors.Unwrap(callErr) != err {
+		return err
+	}
+	return &RevertError{
+		Reason: strings.TrimPrefix(unwrapped.Error(), callErr.Error()+": "),
+		Err:    callErr,
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const rpcHealthCheckTimeout = 10 * time.Second
+
+// RPCPool connects the contract layer to the first healthy endpoint out of an
+// ordered list of candidates and fails over to the next one on demand.
+type RPCPool struct {
+	mu        sync.Mutex
+	chainID   int64
+	endpoints []string
+	next      int
+	active    string
+	client    *ethclient.Client
+}
+
+func NewRPCPool(chainID int64, endpoints ...string) *RPCPool {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		e = strings.TrimSpace(e)
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		unique = append(unique, e)
+	}
+	return &RPCPool{chainID: chainID, endpoints: unique}
+}
+
+// Connect dials the endpoints in order, starting from the first one.
+func (p *RPCPool) Connect(ctx context.Context) (*ethclient.Client, error) {
+	p.mu.Lock()
+	p.next = 0
+	p.mu.Unlock()
+	return p.Failover(ctx)
+}
+
+// Failover drops the active endpoint and dials the next healthy one. It
+// wraps around the list and gives up after trying every endpoint once.
+func (p *RPCPool) Failover(ctx context.Context) (*ethclient.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+		p.active = ""
+	}
+
+	if len(p.endpoints) == 0 {
+		return nil, errors.New("no rpc endpoint configured")
+	}
+
+	var errs []error
+	for range p.endpoints {
+		p.next %= len(p.endpoints)
+		endpoint := p.endpoints[p.next]
+		p.next++
+		client, err := DialHealthyRPC(ctx, endpoint, p.chainID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", RedactRPCEndpoint(endpoint), err))
+			continue
+		}
+		p.active = endpoint
+		p.client = client
+		return client, nil
+	}
+	return nil, fmt.Errorf("no healthy rpc endpoint: %w", errors.Join(errs...))
+}
+
+// Active returns the endpoint currently in use, or an empty string.
+func (p *RPCPool) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active
+}
+
+// Close closes the active client.
+func (p *RPCPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != nil {
+		p.client.Close()
+		p.client = nil
+		p.active = ""
+	}
+}
+
+// DialHealthyRPC dials the endpoint and checks that it serves the expected
+// chain and returns the latest block.
+func DialHealthyRPC(ctx context.Context, endpoint string, chainID int64) (*ethclient.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, rpcHealthCheckTimeout)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("rpc endpoint is invalid or not reachable: %w", err)
+	}
+
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if id.Int64() != chainID {
+		client.Close()
+		return nil, fmt.Errorf("rpc endpoint is on chain %d, expected chain %d", id.Int64(), chainID)
+	}
+
+	if _, err := client.BlockNumber(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return client, nil
+}
+
+// RedactRPCEndpoint strips the path and query of an endpoint, which often
+// carry provider API keys, so it can be logged or displayed.
+func RedactRPCEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func SplitRPCEndpoints(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' '
+	})
+}
@@ -0,0 +1,288 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"activate/secrets"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const NodeShutdownTimeout = 30 * time.Second
+
+var (
+	ErrNodeNotStarted     = errors.New("the node is not started")
+	ErrNodeAlreadyStarted = errors.New("the node is already started")
+	ErrRestartRequired    = errors.New("the running node cannot be stopped, restart the app to use the new node mode")
+	ErrUnknownIdentity    = errors.New("the node address is not known until the node started once")
+)
+
+// Logger receives the progress messages of the service.
+type Logger interface {
+	Log(s string)
+}
+
+type stdLogger struct{}
+
+func (stdLogger) Log(s string) {
+	log.Println(s)
+}
+
+// nodeStopper is implemented by node APIs that can stop in-process. bee-lite
+// keeps its Bee unexported, so until it exposes Shutdown the node only stops
+// with the process.
+type nodeStopper interface {
+	Shutdown() error
+}
+
+// Service runs the node and the data contract without any UI. It keeps its
+// state in the same preferences as the GUI, so the two can be used in turns
+// on the same data dir.
+type Service struct {
+	DataDir string
+	Prefs   Preferences
+	Logger  Logger
+
+	// mu guards the node and the chain connection, every field up to
+	// stopTracking. It is only held to read or swap them: the node starts
+	// under startMu and the contract RPCs dial under connectMu.
+	mu           sync.RWMutex
+	bl           BeeNode
+	opts         *NodeOptions
+	rpcPool      *RPCPool
+	ethClient    *ethclient.Client
+	contract     DataContractInterface
+	contractABI  abi.ABI
+	trackCtx     context.Context
+	stopTracking context.CancelFunc
+
+	startMu   sync.Mutex
+	connectMu sync.Mutex
+	// deploymentBlocks are the deployment blocks found for manifests
+	// without one, by contract address. connectMu guards it.
+	deploymentBlocks map[common.Address]uint64
+
+	outboxMu sync.Mutex
+
+	// NewNode starts the node of Start, a bee-lite node unless set.
+	NewNode func(opts *NodeOptions, password string) (BeeNode, error)
+
+	// OnOutboxUpdate, if set, is called with every change of an outbox
+	// entry, from the goroutine that tracks the transaction.
+	OnOutboxUpdate func(e OutboxEntry)
+}
+
+// NewService loads the deployments of the data dir and returns a service
+// using prefs.
+func NewService(dataDir string, prefs Preferences, logger Logger) *Service {
+	if logger == nil {
+		logger = stdLogger{}
+	}
+	if err := LoadDeployments(dataDir); err != nil {
+		logger.Log(fmt.Sprintf("Failed to load deployments: %v", err))
+	}
+	return &Service{DataDir: dataDir, Prefs: prefs, Logger: logger}
+}
+
+// VaultPath is the vault of the node password in the data dir.
+func (s *Service) VaultPath() string {
+	return filepath.Join(s.DataDir, secrets.FileName)
+}
+
+// KeystoreExists reports whether the node keys were created in the data dir.
+func (s *Service) KeystoreExists() bool {
+	_, err := os.Stat(filepath.Join(s.DataDir, "keys", "swarm.key"))
+	return err == nil
+}
+
+// InitVault sets up a new install with a random node password kept in a vault
+// protected by passphrase. Existing installs are set up by the GUI, which can
+// move an older node password into the vault.
+func (s *Service) InitVault(passphrase string) error {
+	if secrets.Exists(s.VaultPath()) {
+		return errors.New("the node is already set up")
+	}
+	if s.KeystoreExists() {
+		return errors.New("the node keys were created by an older version, open the app once to set up the vault")
+	}
+	password, err := NewNodePassword()
+	if err != nil {
+		return err
+	}
+	vault, err := secrets.Create(s.VaultPath(), passphrase)
+	if err != nil {
+		return err
+	}
+	defer vault.Lock()
+	if err := vault.Set(NodePasswordSecret, []byte(password)); err != nil {
+		_ = os.Remove(s.VaultPath())
+		return fmt.Errorf("failed to store node password: %w", err)
+	}
+	return nil
+}
+
+// NewNodePassword returns a random password for a new keystore.
+func NewNodePassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate node password: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NodePassword opens the vault with passphrase and returns the node password.
+func (s *Service) NodePassword(passphrase string) (string, error) {
+	vault, err := secrets.Open(s.VaultPath(), passphrase)
+	if err != nil {
+		return "", err
+	}
+	defer vault.Lock()
+	password, err := vault.Get(NodePasswordSecret)
+	if err != nil {
+		return "", fmt.Errorf("node password: %w", err)
+	}
+	return string(password), nil
+}
+
+// Network returns the network saved in the preferences.
+func (s *Service) Network() *NetworkProfile {
+	if p := FindNetworkProfile(s.Prefs.String(NetworkPrefKey)); p != nil {
+		return p
+	}
+	return FindNetworkProfile(DefaultNetwork)
+}
+
+// Node returns the running node or nil.
+func (s *Service) Node() BeeNode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bl
+}
+
+// Start starts the node with opts and saves them for the next start. A node
+// that runs in another mode than requested is kept running and a
+// *NodeModeError returned. Light mode on an UltraLightOnly network fails with
+// ErrLightModeUnsupported. The data contract is connected separately.
+func (s *Service) Start(opts *NodeOptions, password string) error {
+	s.startMu.Lock()
+	defer s.startMu.Unlock()
+	if s.Node() != nil {
+		return ErrNodeAlreadyStarted
+	}
+	if opts.SwapEnable && opts.Network.UltraLightOnly {
+		return ErrLightModeUnsupported
+	}
+	s.Logger.Log(opts.WelcomeMessage)
+	s.Logger.Log(fmt.Sprintf("Starting on %s, chain ID: %d, network ID: %d", opts.Network.DisplayName, opts.Network.ChainID, opts.Network.SwarmNetworkID))
+	newNode := s.NewNode
+	if newNode == nil {
+		newNode = startBeeLite
+	}
+	bl, err := newNode(opts, password)
+	if err != nil {
+		return err
+	}
+	s.Prefs.SetString(OverlayAddrPrefKey, bl.OverlayEthAddress().String())
+	s.mu.Lock()
+	s.bl = bl
+	s.opts = opts
+	s.mu.Unlock()
+	if err := CheckNodeMode(bl.BeeNodeMode(), opts.SwapEnable); err != nil {
+		return err
+	}
+	opts.Save(s.Prefs)
+	return nil
+}
+
+// Options returns the options the node was started with, nil if it is not
+// started.
+func (s *Service) Options() *NodeOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.opts
+}
+
+// CanStop reports whether the node can be stopped without exiting.
+func (s *Service) CanStop() bool {
+	var node interface{} = s.Node()
+	_, ok := node.(nodeStopper)
+	return ok
+}
+
+// Stop disconnects from the chain and stops the node, giving up after
+// NodeShutdownTimeout. It returns ErrRestartRequired if the node can only
+// stop with the process.
+func (s *Service) Stop() error {
+	s.mu.Lock()
+	s.disconnect()
+	node := s.bl
+	s.mu.Unlock()
+	if node == nil {
+		return nil
+	}
+	stopper, ok := node.(nodeStopper)
+	if !ok {
+		return ErrRestartRequired
+	}
+	done := make(chan error, 1)
+	go func() { done <- stopper.Shutdown() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("node shutdown: %w", err)
+		}
+	case <-time.After(NodeShutdownTimeout):
+		return fmt.Errorf("node shutdown timed out after %s", NodeShutdownTimeout)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.bl == node {
+		s.bl = nil
+		s.opts = nil
+	}
+	return nil
+}
+
+// Address returns the wallet address of the node, saved by an earlier start
+// when the node is not running.
+func (s *Service) Address() (common.Address, error) {
+	return s.address(s.Node())
+}
+
+// address returns the wallet address of node, or the saved one if node is
+// nil.
+func (s *Service) address(node BeeNode) (common.Address, error) {
+	if node != nil {
+		return node.OverlayEthAddress(), nil
+	}
+	overlayAddr := s.Prefs.String(OverlayAddrPrefKey)
+	if !common.IsHexAddress(overlayAddr) {
+		return common.Address{}, ErrUnknownIdentity
+	}
+	return common.HexToAddress(overlayAddr), nil
+}
+
+func (s *Service) savedAddress(key string) swarm.Address {
+	v := s.Prefs.String(key)
+	if v == "" {
+		return swarm.ZeroAddress
+	}
+	addr, err := swarm.ParseHexAddress(v)
+	if err != nil {
+		s.Logger.Log(fmt.Sprintf("Error decoding stored %s '%s': %v", key, v, err))
+		return swarm.ZeroAddress
+	}
+	return addr
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"activate/contract/deployments"
+	"activate/core/mock"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+// countingLogger counts the messages that start with a prefix.
+type countingLogger struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (l *countingLogger) Log(s string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for prefix := range l.counts {
+		if strings.HasPrefix(s, prefix) {
+			l.counts[prefix]++
+		}
+	}
+}
+
+func (l *countingLogger) count(prefix string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.counts[prefix]
+}
+
+// newSimService returns a service started on a mock node with the key of the
+// sender of c, that sends its transactions to the data contract of c.
+func newSimService(t *testing.T, c *simChain, logger Logger) (*Service, *mock.Node) {
+	t.Helper()
+	node, err := mock.NewNetwork().NewNode(c.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Transactions = c.Transactions
+	dir := t.TempDir()
+	prefs, err := OpenFilePreferences(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(dir, prefs, logger)
+	s.NewNode = func(opts *NodeOptions, password string) (BeeNode, error) {
+		return node, nil
+	}
+	chainID, err := c.Client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := &NetworkProfile{
+		Name:        "simulated",
+		DisplayName: "Simulated chain",
+		ChainID:     chainID.Int64(),
+		RPCEndpoint: c.IPCPath,
+		Deployment: &deployments.Manifest{
+			ChainID:         chainID.Int64(),
+			ContractAddress: c.Address.Hex(),
+			DeploymentBlock: 1,
+		},
+	}
+	if err := s.Start(&NodeOptions{SwapEnable: true, RPCEndpoint: c.IPCPath, Network: profile}, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+	return s, node
+}
+
+// TestSubmitDataConcurrently is meant for -race, the first submissions
+// connect the contract while others read the connection.
+func TestSubmitDataConcurrently(t *testing.T) {
+	c := newSimChain(t)
+	logger := &countingLogger{counts: map[string]int{"Contract RPC connected": 0}}
+	s, _ := newSimService(t, c, logger)
+	owner, actRef, topic := testShare(t)
+	ctx := context.Background()
+
+	const n = 8
+	hashes := make([]common.Hash, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			target := common.HexToAddress(fmt.Sprintf("0x10000000000000000000000000000000000000%02x", i+1))
+			hashes[i], errs[i] = s.SubmitData(ctx, []common.Address{target}, owner, actRef, topic)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = s.Contract()
+			_ = s.EthClient()
+			_ = s.ActiveRPC()
+			_, _ = s.Address()
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[common.Hash]bool)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+		if seen[hashes[i]] {
+			t.Errorf("transaction %s submitted twice", hashes[i].Hex())
+		}
+		seen[hashes[i]] = true
+	}
+	if got := logger.count("Contract RPC connected"); got != 1 {
+		t.Errorf("connected %d times, want once", got)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		entries, err := s.Outbox()
+		if err != nil {
+			t.Fatal(err)
+		}
+		confirmed := 0
+		for _, e := range entries {
+			if e.Status == OutboxConfirmed {
+				confirmed++
+			}
+		}
+		if confirmed == n {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d of %d outbox entries confirmed", confirmed, n)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestSendDataToManyTargets sends a batch that needs more gas than the fixed
+// limit of the contract.
+func TestSendDataToManyTargets(t *testing.T) {
+	c := newSimChain(t)
+	s, _ := newSimService(t, c, nil)
+	owner, actRef, topic := testShare(t)
+	ctx := context.Background()
+
+	targets := make([]common.Address, 250)
+	for i := range targets {
+		targets[i] = common.HexToAddress(fmt.Sprintf("0x1000000000000000000000000000000000%06x", i+1))
+	}
+	receipt, err := s.SendDataToTargets(ctx, targets, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.GasUsed <= transaction.DefaultGasLimit {
+		t.Errorf("used %d gas, want more than the fixed limit", receipt.GasUsed)
+	}
+	shares, err := ParseShares(c.abi, receipt.Logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != len(targets) {
+		t.Errorf("sent to %d of %d targets", len(shares), len(targets))
+	}
+}
+
+// TestReplace speeds up or cancels a transaction held in the pool. Both
+// cancel it at its nonce, a speed-up also sends the share again.
+func TestReplace(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		speedUp bool
+	}{
+		{"speed up", true},
+		{"cancel", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newSimChain(t)
+			s, node := newSimService(t, c, nil)
+			c.Transactions.Hold = true
+			owner, actRef, topic := testShare(t)
+			ctx := context.Background()
+			target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+			txHash, err := s.SubmitData(ctx, []common.Address{target}, owner, actRef, topic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			replace := s.Cancel
+			if tc.speedUp {
+				replace = s.SpeedUp
+			}
+			sent, err := replace(ctx, txHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := s.OutboxEntry(txHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(e.Cancels) != 1 {
+				t.Fatalf("%d cancellations, want 1", len(e.Cancels))
+			}
+			cancellation := common.HexToHash(e.Cancels[0])
+			original, err := node.TransactionService().StoredTransaction(txHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored, err := node.TransactionService().StoredTransaction(cancellation)
+			if err != nil {
+				t.Fatalf("cancellation not stored by the transaction service: %v", err)
+			}
+			if stored.Nonce != original.Nonce || stored.GasTipCap.Cmp(original.GasTipCap) <= 0 {
+				t.Errorf("cancellation nonce %d tip %s, original nonce %d tip %s", stored.Nonce, stored.GasTipCap, original.Nonce, original.GasTipCap)
+			}
+
+			c.Backend.Commit()
+			if err := s.RecheckOutbox(ctx); !errors.Is(err, ErrTransactionCancelled) {
+				t.Errorf("recheck: %v, want %v", err, ErrTransactionCancelled)
+			}
+			if e, _ = s.OutboxEntry(txHash); e.Status != OutboxCancelled || e.MinedTx != cancellation.Hex() {
+				t.Errorf("entry %s mined in %s, want %s mined in %s", e.Status, e.MinedTx, OutboxCancelled, cancellation.Hex())
+			}
+			if !tc.speedUp {
+				if sent != cancellation {
+					t.Errorf("cancel returned %s, want %s", sent.Hex(), cancellation.Hex())
+				}
+				return
+			}
+			if e.ResentAs != sent.Hex() {
+				t.Errorf("sent again as %s, want %s", e.ResentAs, sent.Hex())
+			}
+			resent, err := s.OutboxEntry(sent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resent.Status != OutboxConfirmed || resent.Target != target.Hex() || resent.Topic != topic {
+				t.Errorf("entry sent again %+v, want confirmed to %s", resent, target.Hex())
+			}
+		})
+	}
+}
+
+// TestReplacedElsewhere mines another transaction at the nonce of a pending
+// one.
+func TestReplacedElsewhere(t *testing.T) {
+	c := newSimChain(t)
+	s, node := newSimService(t, c, nil)
+	c.Transactions.Hold = true
+	owner, actRef, topic := testShare(t)
+	ctx := context.Background()
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	txHash, err := s.SubmitData(ctx, []common.Address{target}, owner, actRef, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := node.TransactionService().StoredTransaction(txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID, err := c.Client.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     stored.Nonce,
+		GasTipCap: raise(stored.GasTipCap, 50),
+		GasFeeCap: raise(stored.GasFeeCap, 50),
+		Gas:       21000,
+		To:        &c.Sender,
+		Value:     big.NewInt(0),
+	}), types.LatestSignerForChainID(chainID), c.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Client.SendTransaction(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	c.Backend.Commit()
+
+	if err := s.RecheckOutbox(ctx); !errors.Is(err, ErrTransactionReplaced) {
+		t.Errorf("recheck: %v, want %v", err, ErrTransactionReplaced)
+	}
+	if e, _ := s.OutboxEntry(txHash); e.Status != OutboxFailed {
+		t.Errorf("entry %s, want %s", e.Status, OutboxFailed)
+	}
+}
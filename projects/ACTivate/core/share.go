@@ -0,0 +1,132 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const (
+	dataSentToTargetEvent = "DataSentToTarget"
+	// publicKeyHexLen is an uncompressed public key, 04 followed by X and Y.
+	publicKeyHexLen = 130
+	referenceHexLen = 2 * swarm.HashSize
+)
+
+var ErrNotShare = errors.New("log is not a DataSentToTarget event")
+
+// Share is a reference sent to a target through the data contract. The topic
+// carries the publisher public key followed by the reference, hex encoded, and
+// ActRef is the history reference of the access control list.
+type Share struct {
+	From        common.Address
+	To          common.Address
+	Owner       []byte
+	ActRef      []byte
+	Topic       string
+	Publisher   string
+	Reference   string
+	BlockNumber uint64
+	TxHash      common.Hash
+}
+
+// ParseShare decodes a DataSentToTarget event log.
+func ParseShare(contractABI abi.ABI, vLog types.Log) (*Share, error) {
+	event, ok := contractABI.Events[dataSentToTargetEvent]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in ABI", dataSentToTargetEvent)
+	}
+	if len(vLog.Topics) == 0 || vLog.Topics[0] != event.ID {
+		return nil, ErrNotShare
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	values := make(map[string]interface{})
+	if err := abi.ParseTopicsIntoMap(values, indexed, vLog.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("parse %s topics: %w", dataSentToTargetEvent, err)
+	}
+	if err := event.Inputs.UnpackIntoMap(values, vLog.Data); err != nil {
+		return nil, fmt.Errorf("unpack %s data: %w", dataSentToTargetEvent, err)
+	}
+
+	s := &Share{BlockNumber: vLog.BlockNumber, TxHash: vLog.TxHash}
+	s.From, _ = values["from"].(common.Address)
+	s.To, _ = values["to"].(common.Address)
+	if owner, ok := values["owner"].([32]byte); ok {
+		s.Owner = owner[:]
+	}
+	if actRef, ok := values["actref"].([32]byte); ok {
+		s.ActRef = actRef[:]
+	}
+	s.Topic, _ = values["topic"].(string)
+	s.Publisher, s.Reference, _ = SplitShareTopic(s.Topic)
+	return s, nil
+}
+
+// ParseShares decodes the DataSentToTarget events among logs, such as the
+// logs of a sendDataToTargets receipt, and skips the other logs.
+func ParseShares(contractABI abi.ABI, logs []*types.Log) ([]*Share, error) {
+	var shares []*Share
+	for _, vLog := range logs {
+		share, err := ParseShare(contractABI, *vLog)
+		if errors.Is(err, ErrNotShare) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+// SplitShareTopic splits a share topic into the publisher public key and the
+// reference. Topics that do not start with an uncompressed key are read as a
+// 128 character key without prefix.
+func SplitShareTopic(topic string) (publisher, reference string, ok bool) {
+	keyLen := publicKeyHexLen
+	if len(topic) < 2 || topic[:2] != "04" {
+		keyLen = publicKeyHexLen - 2
+	}
+	if len(topic) < keyLen+referenceHexLen {
+		return "", "", false
+	}
+	return topic[:keyLen], topic[keyLen : keyLen+referenceHexLen], true
+}
+
+// ShareTopic builds the topic of a share from the publisher public key and
+// the reference.
+func ShareTopic(publisher []byte, reference swarm.Address) string {
+	return hex.EncodeToString(publisher) + reference.String()
+}
+
+// ParsePublicKey parses a hex encoded uncompressed public key, with or
+// without the 0x and 04 prefixes.
+func ParsePublicKey(s string) (*ecdsa.PublicKey, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s) == publicKeyHexLen-2 {
+		s = "04" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key hex: %w", err)
+	}
+	key, err := crypto.UnmarshalPubkey(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ECDSA public key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"testing"
+
+	"activate/contract/deployments"
+	"activate/core/mock"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// simChain is a simulated chain with a data contract client for its sender.
+type simChain struct {
+	*mock.Chain
+	abi      abi.ABI
+	contract DataContractInterface
+}
+
+func newSimChain(t *testing.T) *simChain {
+	t.Helper()
+	chain := mock.NewChain(t)
+	contractABI, err := deployments.DefaultABI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &simChain{
+		Chain:    chain,
+		abi:      contractABI,
+		contract: NewDataContract(chain.Sender, chain.Address, contractABI, chain.Transactions, true, 0),
+	}
+}
@@ -0,0 +1,278 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethersphere/bee/v2/pkg/sctx"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+)
+
+const (
+	trackInterval = 5 * time.Second
+	// replacementBumpPercent raises the fee cap of a cancellation, nodes
+	// only accept a replacement that pays at least 10% more.
+	replacementBumpPercent = 15
+)
+
+var (
+	ErrNotInOutbox          = errors.New("the transaction is not in the outbox")
+	ErrNotPending           = errors.New("the transaction is no longer pending")
+	ErrTransactionCancelled = errors.New("the transaction was cancelled")
+	ErrTransactionReplaced  = errors.New("the transaction was replaced by another one with the same nonce")
+)
+
+// trackInBackground tracks txHash until it is mined or the contract is
+// disconnected. An entry still pending then is tracked again by
+// RecheckOutbox.
+func (s *Service) trackInBackground(txHash common.Hash) {
+	ctx := s.chain().trackCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	go func() {
+		if _, err := s.track(ctx, txHash); err != nil && ctx.Err() == nil {
+			s.Logger.Log(fmt.Sprintf("Transaction %s: %v", txHash.Hex(), err))
+		}
+	}()
+}
+
+// track polls for the receipt of txHash or of one of its cancellations and
+// records the outcome in the outbox. An error of ctx leaves the entry
+// pending.
+func (s *Service) track(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	node := s.Node()
+	if node == nil {
+		return nil, ErrNodeNotStarted
+	}
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := node.TransactionService().StoredTransaction(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("load transaction: %w", err)
+	}
+
+	ticker := time.NewTicker(trackInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := s.findReceipt(ctx, txHash)
+		switch {
+		case err != nil:
+			s.Logger.Log(fmt.Sprintf("Receipt of %s: %v", txHash.Hex(), err))
+		case receipt != nil:
+			return s.recordReceipt(ctx, c.contract, txHash, receipt)
+		default:
+			nonce, err := c.client.NonceAt(ctx, node.OverlayEthAddress(), nil)
+			if err != nil || nonce <= stored.Nonce {
+				break
+			}
+			// the nonce is used, by one of ours whose receipt lags behind or
+			// by a transaction sent from elsewhere
+			replaced, err := s.replacedElsewhere(ctx, c.client, txHash)
+			if err != nil {
+				s.Logger.Log(fmt.Sprintf("Transactions of %s: %v", txHash.Hex(), err))
+			}
+			if replaced {
+				s.updateOutbox(txHash, func(e *OutboxEntry) {
+					e.Status = OutboxFailed
+					e.Error = ErrTransactionReplaced.Error()
+				})
+				return nil, ErrTransactionReplaced
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// findReceipt returns the receipt of the transaction of the outbox entry
+// txHash or of a cancellation, nil if none is mined yet.
+func (s *Service) findReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	e, err := s.OutboxEntry(txHash)
+	if err != nil {
+		return nil, err
+	}
+	client := s.EthClient()
+	if client == nil {
+		return nil, ErrNoDataContract
+	}
+	for _, h := range e.Hashes() {
+		receipt, err := client.TransactionReceipt(ctx, h)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return receipt, nil
+	}
+	return nil, nil
+}
+
+// replacedElsewhere reports whether none of the transactions of the outbox
+// entry txHash was mined, once their nonce is used: the transaction mined
+// with it is not one of ours.
+func (s *Service) replacedElsewhere(ctx context.Context, client *ethclient.Client, txHash common.Hash) (bool, error) {
+	e, err := s.OutboxEntry(txHash)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range e.Hashes() {
+		_, pending, err := client.TransactionByHash(ctx, h)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if !pending {
+			// mined, its receipt is on the way
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) recordReceipt(ctx context.Context, contract DataContractInterface, txHash common.Hash, receipt *types.Receipt) (*types.Receipt, error) {
+	var err error
+	status := OutboxConfirmed
+	e, _ := s.OutboxEntry(txHash)
+	switch {
+	case slices.Contains(e.Cancels, receipt.TxHash.Hex()):
+		status, err = OutboxCancelled, ErrTransactionCancelled
+	case receipt.Status == types.ReceiptStatusFailed:
+		status, err = OutboxReverted, contract.ExplainRevert(ctx, txHash)
+	}
+	s.updateOutbox(txHash, func(e *OutboxEntry) {
+		e.Status = status
+		if err != nil {
+			e.Error = err.Error()
+		}
+		if receipt.TxHash != txHash {
+			e.MinedTx = receipt.TxHash.Hex()
+		}
+		e.BlockNumber = receipt.BlockNumber.Uint64()
+		e.GasUsed = receipt.GasUsed
+	})
+	if err != nil {
+		return nil, fmt.Errorf("send data to target: %w", err)
+	}
+	return receipt, nil
+}
+
+// SpeedUp cancels the pending transaction txHash and sends its call again at
+// fast speed, recorded as a new outbox entry that the one of txHash points to
+// with ResentAs. The transaction service of bee only sends at its next nonce,
+// so the call cannot take the nonce of txHash. It returns the hash of the new
+// transaction.
+func (s *Service) SpeedUp(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	node := s.Node()
+	if node == nil {
+		return common.Hash{}, ErrNodeNotStarted
+	}
+	e, err := s.OutboxEntry(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if e.Status != OutboxPending || len(e.Cancels) != 0 {
+		return common.Hash{}, ErrNotPending
+	}
+	txService := node.TransactionService()
+	stored, err := txService.StoredTransaction(txHash)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("load transaction: %w", err)
+	}
+	if _, err := s.Cancel(ctx, txHash); err != nil {
+		return common.Hash{}, err
+	}
+
+	resent, err := txService.Send(ctx, &transaction.TxRequest{
+		To:          stored.To,
+		Data:        stored.Data,
+		Value:       stored.Value,
+		GasLimit:    stored.GasLimit,
+		Description: stored.Description,
+	}, SpeedFast.TipBoostPercent())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send again: %w", err)
+	}
+	s.updateOutbox(resent, func(n *OutboxEntry) {
+		n.Target = e.Target
+		n.Targets = e.Targets
+		n.Reference = e.Reference
+		n.Topic = e.Topic
+		n.Status = OutboxPending
+		n.Timestamp = time.Now()
+	})
+	s.updateOutbox(txHash, func(e *OutboxEntry) {
+		e.ResentAs = resent.Hex()
+	})
+	s.trackInBackground(resent)
+	s.Logger.Log(fmt.Sprintf("Sent %s again as %s", txHash.Hex(), resent.Hex()))
+	return resent, nil
+}
+
+// Cancel replaces the pending transaction txHash by a transfer of nothing to
+// the node itself at the same nonce, so that the share is not sent. The
+// transaction service of the node sends the cancellation, paying more than
+// the last transaction sent with the nonce and at least the current fees at
+// fast speed. It returns the hash of the cancellation.
+func (s *Service) Cancel(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	node := s.Node()
+	if node == nil {
+		return common.Hash{}, ErrNodeNotStarted
+	}
+	e, err := s.OutboxEntry(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if e.Status != OutboxPending {
+		return common.Hash{}, ErrNotPending
+	}
+	c, err := s.connectedContract(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	txService := node.TransactionService()
+	hashes := e.Hashes()
+	last := hashes[len(hashes)-1]
+	stored, err := txService.StoredTransaction(last)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("load transaction: %w", err)
+	}
+
+	// the service adds the tip to the gas price for the fee cap
+	price := raise(stored.GasFeeCap, replacementBumpPercent)
+	if suggested, err := c.client.SuggestGasPrice(ctx); err == nil {
+		if suggested = raise(suggested, SpeedFast.TipBoostPercent()); suggested.Cmp(price) > 0 {
+			price = suggested
+		}
+	}
+	cancellation, err := txService.CancelTransaction(sctx.SetGasPrice(ctx, price), last)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send cancellation: %w", err)
+	}
+	s.updateOutbox(txHash, func(e *OutboxEntry) {
+		e.Cancels = append(e.Cancels, cancellation.Hex())
+	})
+	s.Logger.Log(fmt.Sprintf("Cancelling %s with %s", txHash.Hex(), cancellation.Hex()))
+	return cancellation, nil
+}
+
+func raise(v *big.Int, percent int) *big.Int {
+	v = new(big.Int).Mul(v, big.NewInt(int64(percent)+100))
+	return v.Div(v, big.NewInt(100))
+}
@@ -0,0 +1,125 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+)
+
+// DefaultDerivationPath is the first account of the Ethereum BIP-44 path, so
+// the node address matches what wallets show for the same mnemonic.
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+const hardenedOffset = 0x80000000
+
+var errInvalidChildKey = errors.New("derived key is invalid, try the next index")
+
+// DeriveKey derives the secp256k1 key of the mnemonic at the given BIP-32
+// path.
+func DeriveKey(mnemonic, path string) (*ecdsa.PrivateKey, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := Seed(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveSeedKey(seed, indexes)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.DecodeSecp256k1PrivateKey(key)
+}
+
+// deriveSeedKey returns the private key of the seed at the path indexes.
+func deriveSeedKey(seed []byte, indexes []uint32) ([]byte, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	for _, index := range indexes {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, key...)
+	} else {
+		pk, err := crypto.DecodeSecp256k1PrivateKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.EncodeSecp256k1PublicKey(&pk.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	if err := checkKey(sum[:32]); err != nil {
+		return nil, nil, err
+	}
+	n := curveOrder()
+	child := new(big.Int).SetBytes(sum[:32])
+	child.Add(child, new(big.Int).SetBytes(key))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errInvalidChildKey
+	}
+	return child.FillBytes(make([]byte, 32)), sum[32:], nil
+}
+
+func checkKey(key []byte) error {
+	k := new(big.Int).SetBytes(key)
+	if k.Sign() == 0 || k.Cmp(curveOrder()) >= 0 {
+		return errInvalidChildKey
+	}
+	return nil
+}
+
+func curveOrder() *big.Int {
+	return crypto.Secp256k1PrivateKeyFromBytes([]byte{1}).Curve.Params().N
+}
+
+func parsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(p, "'") {
+			offset = hardenedOffset
+			p = strings.TrimSuffix(p, "'")
+		}
+		v, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || v >= hardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q", path)
+		}
+		indexes = append(indexes, uint32(v)+offset)
+	}
+	return indexes, nil
+}
@@ -0,0 +1,61 @@
+package identity
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+)
+
+// TestBIP32Vector checks test vector 1 of BIP-32 down its whole path.
+func TestBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	for path, want := range map[string]string{
+		"m":                      "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		"m/0'":                   "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		"m/0'/1":                 "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		"m/0'/1/2'":              "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+		"m/0'/1/2'/2":            "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+		"m/0'/1/2'/2/1000000000": "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+	} {
+		indexes, err := parsePath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := deriveSeedKey(seed, indexes)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if got := hex.EncodeToString(key); got != want {
+			t.Errorf("key of %s = %s, want %s", path, got, want)
+		}
+	}
+}
+
+// TestDeriveKey checks the BIP-44 Ethereum accounts wallets show for the
+// "abandon ... about" mnemonic.
+func TestDeriveKey(t *testing.T) {
+	mnemonic := bip39Vectors[0].mnemonic
+	for path, want := range map[string]string{
+		DefaultDerivationPath: "9858effd232b4033e47d90003d41ec34ecaeda94",
+		"m/44'/60'/0'/0/1":    "6fac4d18c912343bf86fa7049364dd4e424ab9c0",
+	} {
+		key, err := DeriveKey(mnemonic, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := crypto.NewEthereumAddress(key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(addr); got != want {
+			t.Errorf("address of %s = %s, want %s", path, got, want)
+		}
+	}
+
+	for _, path := range []string{"", "44'/60'", "m/x", "m/2147483648", "m/-1"} {
+		if _, err := DeriveKey(mnemonic, path); err == nil {
+			t.Errorf("derived the invalid path %q", path)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/keystore"
+	filekeystore "github.com/ethersphere/bee/v2/pkg/keystore/file"
+)
+
+const (
+	keysDir      = "keys"
+	swarmKeyName = "swarm"
+)
+
+// ErrKeyExists is returned when the data directory already has a swarm key.
+var ErrKeyExists = errors.New("a node key already exists")
+
+// fixedEDG hands a known key to the keystore instead of generating one, so
+// the keystore encrypts it in the same format bee-lite reads.
+type fixedEDG struct {
+	key *ecdsa.PrivateKey
+}
+
+func (e fixedEDG) Generate() (*ecdsa.PrivateKey, error) { return e.key, nil }
+
+func (e fixedEDG) Encode(k *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.EDGSecp256_K1.Encode(k)
+}
+
+func (e fixedEDG) Decode(data []byte) (*ecdsa.PrivateKey, error) {
+	return crypto.EDGSecp256_K1.Decode(data)
+}
+
+var _ keystore.EDG = fixedEDG{}
+
+// WriteSwarmKey stores key as the swarm key of the node in dataDir, encrypted
+// with the node password.
+func WriteSwarmKey(dataDir, password string, key *ecdsa.PrivateKey) error {
+	ks := filekeystore.New(filepath.Join(dataDir, keysDir))
+	exists, err := ks.Exists(swarmKeyName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrKeyExists
+	}
+	if _, err := ks.SetKey(swarmKeyName, password, fixedEDG{key: key}); err != nil {
+		return fmt.Errorf("write swarm key: %w", err)
+	}
+	return nil
+}
+
+// Address returns the Ethereum address of the key as hex.
+func Address(key *ecdsa.PrivateKey) (string, error) {
+	addr, err := crypto.NewEthereumAddress(key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("0x%x", addr), nil
+}
@@ -0,0 +1,124 @@
+// Package identity creates and recovers the node identity from a BIP-39
+// mnemonic and writes it into a keystore bee-lite can open.
+package identity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// DefaultWords is the length of newly generated mnemonics.
+const DefaultWords = 12
+
+var (
+	ErrInvalidMnemonic = errors.New("invalid recovery phrase")
+	ErrChecksum        = errors.New("recovery phrase checksum mismatch")
+)
+
+//go:embed english.txt
+var englishWords string
+
+var (
+	wordList  = strings.Fields(englishWords)
+	wordIndex = func() map[string]int {
+		m := make(map[string]int, len(wordList))
+		for i, w := range wordList {
+			m[w] = i
+		}
+		return m
+	}()
+)
+
+// NewMnemonic returns a random English mnemonic with the given number of
+// words, which must be 12, 15, 18, 21 or 24.
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("unsupported mnemonic length %d", words)
+	}
+	entropy := make([]byte, words*4/3)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("generate entropy: %w", err)
+	}
+	return entropyToMnemonic(entropy), nil
+}
+
+// NormalizeMnemonic lower-cases the phrase and collapses whitespace.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// ValidateMnemonic checks the words and the checksum of the phrase.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicToEntropy(mnemonic)
+	return err
+}
+
+// Seed returns the BIP-39 seed of the mnemonic. The optional BIP-39
+// passphrase is not supported, the seed always uses an empty one.
+func Seed(mnemonic string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return seed(mnemonic, ""), nil
+}
+
+func seed(mnemonic, passphrase string) []byte {
+	return pbkdf2.Key([]byte(NormalizeMnemonic(mnemonic)), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+}
+
+func entropyToMnemonic(entropy []byte) string {
+	checksumBits := len(entropy) * 8 / 32
+	hash := sha256.Sum256(entropy)
+
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, uint(checksumBits))
+	bits.Or(bits, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	count := (len(entropy)*8 + checksumBits) / 11
+	words := make([]string, count)
+	mask := big.NewInt(2047)
+	for i := count - 1; i >= 0; i-- {
+		words[i] = wordList[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+	return strings.Join(words, " ")
+}
+
+func mnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(NormalizeMnemonic(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("%w: expected 12 to 24 words, got %d", ErrInvalidMnemonic, len(words))
+	}
+
+	bits := new(big.Int)
+	for _, w := range words {
+		idx, ok := wordIndex[w]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, w)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(idx)))
+	}
+
+	checksumBits := len(words) * 11 / 33
+	entropyLen := (len(words)*11 - checksumBits) / 8
+	checksum := new(big.Int).And(bits, big.NewInt(int64(1<<checksumBits-1)))
+	bits.Rsh(bits, uint(checksumBits))
+
+	entropy := make([]byte, entropyLen)
+	bits.FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, ErrChecksum
+	}
+	return entropy, nil
+}
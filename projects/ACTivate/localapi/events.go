@@ -0,0 +1,74 @@
+package localapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const keepAliveInterval = 30 * time.Second
+
+// events streams the shares sent to the node as server-sent events, one
+// "share" event per DataSentToTarget log. The stream ends with an "error"
+// event when the subscription fails, clients reconnect to resubscribe.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+	ctx := r.Context()
+	logs := make(chan types.Log)
+	sub, err := s.svc.SubscribeShares(ctx, logs)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			if err != nil {
+				writeEvent(w, "error", map[string]string{"error": err.Error()})
+				flusher.Flush()
+			}
+			return
+		case vLog, ok := <-logs:
+			if !ok {
+				return
+			}
+			share, err := s.svc.ReceiveShare(vLog)
+			if err != nil {
+				s.svc.Logger.Log(fmt.Sprintf("Local API skipping log: %v", err))
+				continue
+			}
+			writeEvent(w, "share", newShareJSON(share))
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
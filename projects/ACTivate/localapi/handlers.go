@@ -0,0 +1,449 @@
+package localapi
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"activate/core"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+// maxRequestBody caps the JSON bodies, uploads are not limited.
+const maxRequestBody = 1 << 20
+
+type nodeResponse struct {
+	Mode      string `json:"mode"`
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"`
+	Network   string `json:"network"`
+	ChainID   int64  `json:"chainId"`
+	Peers     int    `json:"peers"`
+}
+
+type groupResponse struct {
+	Reference string   `json:"reference"`
+	History   string   `json:"history"`
+	Grantees  []string `json:"grantees"`
+}
+
+type createGroupRequest struct {
+	Grantees []string `json:"grantees"`
+	Batch    string   `json:"batch"`
+}
+
+type updateGroupRequest struct {
+	Add     []string `json:"add"`
+	Revoke  []string `json:"revoke"`
+	History string   `json:"history"`
+	Batch   string   `json:"batch"`
+}
+
+type uploadResponse struct {
+	Reference string `json:"reference"`
+	History   string `json:"history,omitempty"`
+	Batch     string `json:"batch"`
+	Reason    string `json:"reason"`
+}
+
+// shareRequest names the recipients with to, targets, or group to send to
+// every grantee.
+type shareRequest struct {
+	To        string   `json:"to"`
+	Targets   []string `json:"targets"`
+	Group     bool     `json:"group"`
+	Reference string   `json:"reference"`
+	Speed     string   `json:"speed"`
+}
+
+type shareResponse struct {
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+type shareJSON struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Owner       string `json:"owner"`
+	History     string `json:"history"`
+	Publisher   string `json:"publisher"`
+	Reference   string `json:"reference"`
+	Topic       string `json:"topic"`
+	BlockNumber uint64 `json:"blockNumber"`
+	TxHash      string `json:"txHash"`
+}
+
+func newShareJSON(s *core.Share) shareJSON {
+	return shareJSON{
+		From:        s.From.Hex(),
+		To:          s.To.Hex(),
+		Owner:       hex.EncodeToString(s.Owner),
+		History:     hex.EncodeToString(s.ActRef),
+		Publisher:   s.Publisher,
+		Reference:   s.Reference,
+		Topic:       s.Topic,
+		BlockNumber: s.BlockNumber,
+		TxHash:      s.TxHash.Hex(),
+	}
+}
+
+func (s *Server) node(w http.ResponseWriter, r *http.Request) {
+	bl := s.svc.Node()
+	if bl == nil {
+		writeError(w, http.StatusServiceUnavailable, core.ErrNodeNotStarted)
+		return
+	}
+	network := s.svc.Network()
+	writeJSON(w, http.StatusOK, nodeResponse{
+		Mode:      bl.BeeNodeMode().String(),
+		Address:   bl.OverlayEthAddress().Hex(),
+		PublicKey: hex.EncodeToString(crypto.EncodeSecp256k1PublicKey(bl.PublicKey())),
+		Network:   network.Name,
+		ChainID:   network.ChainID,
+		Peers:     bl.ConnectedPeerCount(),
+	})
+}
+
+func (s *Server) group(w http.ResponseWriter, r *http.Request) {
+	grantees, err := s.svc.Grantees(r.Context())
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, groupResponse{
+		Reference: s.svc.GranteeListRef().String(),
+		History:   s.svc.HistoryRef().String(),
+		Grantees:  grantees,
+	})
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var req createGroupRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Grantees) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("grantees cannot be empty"))
+		return
+	}
+	batchHex, ok := s.batch(w, core.BatchGroupGrantees, req.Batch)
+	if !ok {
+		return
+	}
+	l, err := s.svc.CreateGroup(r.Context(), batchHex, req.Grantees)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, groupResponse{Reference: l.Ref.String(), History: l.HistoryRef.String(), Grantees: req.Grantees})
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var req updateGroupRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Add) == 0 && len(req.Revoke) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("nothing to add or revoke"))
+		return
+	}
+	history := s.svc.HistoryRef()
+	if req.History != "" {
+		var err error
+		if history, err = swarm.ParseHexAddress(req.History); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("history reference: %w", err))
+			return
+		}
+	}
+	batchHex, ok := s.batch(w, core.BatchGroupGrantees, req.Batch)
+	if !ok {
+		return
+	}
+	l, err := s.svc.UpdateGroup(r.Context(), batchHex, history, req.Add, req.Revoke)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	grantees, err := s.svc.Grantees(r.Context())
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, groupResponse{Reference: l.Ref.String(), History: l.HistoryRef.String(), Grantees: grantees})
+}
+
+func (s *Server) uploads(w http.ResponseWriter, r *http.Request) {
+	uploads, err := s.svc.Uploads()
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	if uploads == nil {
+		uploads = []core.Upload{}
+	}
+	writeJSON(w, http.StatusOK, uploads)
+}
+
+// upload stores the request body. The file name is the name query parameter,
+// act=true protects it with the grantee list.
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name := q.Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("the name query parameter is required"))
+		return
+	}
+	act := q.Get("act") == "true"
+	mimetype := r.Header.Get("Content-Type")
+	if mimetype == "" {
+		mimetype = "application/octet-stream"
+	}
+
+	var body io.Reader = r.Body
+	size := r.ContentLength
+	if size < 0 {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		body, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	stamp, reason, err := s.svc.SelectBatch(core.BatchGroupUploads, q.Get("batch"))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	batchHex := core.BatchHex(stamp)
+	ref, err := s.svc.Upload(r.Context(), batchHex, name, mimetype, size, act, body)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	resp := uploadResponse{Reference: ref.String(), Batch: batchHex, Reason: reason}
+	if act {
+		resp.History = s.svc.HistoryRef().String()
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// download streams the content of ref. Access controlled content needs the
+// publisher and history query parameters.
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	ref, err := swarm.ParseHexAddress(r.PathValue("ref"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("reference: %w", err))
+		return
+	}
+	q := r.URL.Query()
+	if (q.Get("publisher") == "") != (q.Get("history") == "") {
+		writeError(w, http.StatusBadRequest, errors.New("publisher and history go together"))
+		return
+	}
+	var publisher *ecdsa.PublicKey
+	var history *swarm.Address
+	if q.Get("publisher") != "" {
+		if publisher, err = core.ParsePublicKey(q.Get("publisher")); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		h, err := swarm.ParseHexAddress(q.Get("history"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("history reference: %w", err))
+			return
+		}
+		history = &h
+	}
+	content, err := s.svc.Download(r.Context(), ref, publisher, history)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeContent(w, content)
+}
+
+func (s *Server) sendShare(w http.ResponseWriter, r *http.Request) {
+	var req shareRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	addrs := req.Targets
+	if req.To != "" {
+		addrs = append(addrs, req.To)
+	}
+	if (len(addrs) == 0) == !req.Group {
+		writeError(w, http.StatusBadRequest, errors.New("name the recipients with to or targets, or set group"))
+		return
+	}
+	targets := make([]common.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		if !common.IsHexAddress(addr) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%s is not a valid address", addr))
+			return
+		}
+		targets = append(targets, common.HexToAddress(addr))
+	}
+	ref, err := swarm.ParseHexAddress(req.Reference)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("reference: %w", err))
+		return
+	}
+	speed, err := core.ParseSpeed(req.Speed)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ctx := core.WithSpeed(r.Context(), speed)
+	if req.Group {
+		if targets, err = s.svc.GroupAddresses(ctx); err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+	}
+	receipts, err := s.svc.SendShares(ctx, targets, ref)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	resp := make([]shareResponse, 0, len(receipts))
+	for _, receipt := range receipts {
+		resp = append(resp, shareResponse{TxHash: receipt.TxHash.Hex(), BlockNumber: receipt.BlockNumber.Uint64()})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) outbox(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.svc.Outbox()
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+type replaceResponse struct {
+	TxHash string `json:"txHash"`
+}
+
+// replaceOutbox speeds up or cancels the pending share {tx}, the outcome
+// shows in the outbox.
+func (s *Server) replaceOutbox(cancel bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txHash := common.HexToHash(r.PathValue("tx"))
+		replace := s.svc.SpeedUp
+		if cancel {
+			replace = s.svc.Cancel
+		}
+		replacement, err := replace(r.Context(), txHash)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, replaceResponse{TxHash: replacement.Hex()})
+	}
+}
+
+// inbox lists the shares received, oldest first. The position in the list,
+// counted from 1, fetches the content of a share.
+func (s *Server) inbox(w http.ResponseWriter, r *http.Request) {
+	shares, err := s.svc.Inbox(r.Context())
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	list := make([]shareJSON, 0, len(shares))
+	for _, share := range shares {
+		list = append(list, newShareJSON(share))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) inboxFetch(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("share number: %w", err))
+		return
+	}
+	shares, err := s.svc.Inbox(r.Context())
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	if n < 1 || n > len(shares) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no share %d, the inbox has %d", n, len(shares)))
+		return
+	}
+	content, err := s.svc.Fetch(r.Context(), shares[n-1])
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeContent(w, content)
+}
+
+// batch returns the batch to use for an operation of group, writing the error
+// response if there is none.
+func (s *Server) batch(w http.ResponseWriter, group, batchHex string) (string, bool) {
+	stamp, reason, err := s.svc.SelectBatch(group, batchHex)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return "", false
+	}
+	s.svc.Logger.Log(fmt.Sprintf("Local API using batch %x (%s)", stamp.ID(), reason))
+	return core.BatchHex(stamp), true
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, core.ErrNodeNotStarted):
+		return http.StatusServiceUnavailable
+	case errors.As(err, new(*core.RevertError)):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, core.ErrNoGranteeList), errors.Is(err, core.ErrNoShare), errors.Is(err, core.ErrNotInOutbox):
+		return http.StatusNotFound
+	case errors.Is(err, core.ErrNoDataContract):
+		return http.StatusNotImplemented
+	case errors.Is(err, core.ErrNoUsableBatch), errors.Is(err, core.ErrNoBatchSelected), errors.Is(err, core.ErrUnknownIdentity),
+		errors.Is(err, core.ErrNoHistory), errors.Is(err, core.ErrNoTargets), errors.Is(err, core.ErrNotPending):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func writeContent(w http.ResponseWriter, content io.Reader) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
@@ -0,0 +1,156 @@
+// Package localapi serves a running node over HTTP on the loopback interface,
+// so scripts and companion apps can use the groups, uploads and shares of the
+// app. Every request needs the token kept in the data dir.
+package localapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"activate/core"
+)
+
+const (
+	// DefaultAddr is the address the API listens on unless configured.
+	DefaultAddr = "127.0.0.1:1640"
+	// TokenFile holds the API token in the data dir.
+	TokenFile = "api-token"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+var ErrNotLoopback = errors.New("the local API only listens on a loopback address")
+
+// Server is the HTTP API of a core service.
+type Server struct {
+	svc   *core.Service
+	token string
+}
+
+// New returns a server for svc that accepts token as bearer token.
+func New(svc *core.Service, token string) *Server {
+	return &Server{svc: svc, token: token}
+}
+
+// LoadToken reads the API token of the data dir, creating it on first use.
+// The file is only readable by the user, like the vault.
+func LoadToken(dataDir string) (string, error) {
+	path := filepath.Join(dataDir, TokenFile)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("read API token: %w", err)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write API token: %w", err)
+	}
+	return token, nil
+}
+
+// CheckAddr returns ErrNotLoopback if addr is not on the loopback interface.
+func CheckAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopbackHost(host) {
+		return ErrNotLoopback
+	}
+	return nil
+}
+
+// ListenAndServe serves the API on addr until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	if err := CheckAddr(addr); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	s.svc.Logger.Log(fmt.Sprintf("Local API listening on http://%s", ln.Addr()))
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the routes of the API behind the token check.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/node", s.node)
+	mux.HandleFunc("GET /v1/group", s.group)
+	mux.HandleFunc("POST /v1/group", s.createGroup)
+	mux.HandleFunc("PATCH /v1/group", s.updateGroup)
+	mux.HandleFunc("GET /v1/uploads", s.uploads)
+	mux.HandleFunc("POST /v1/uploads", s.upload)
+	mux.HandleFunc("GET /v1/content/{ref}", s.download)
+	mux.HandleFunc("POST /v1/shares", s.sendShare)
+	mux.HandleFunc("GET /v1/outbox", s.outbox)
+	mux.HandleFunc("POST /v1/outbox/{tx}/speedup", s.replaceOutbox(false))
+	mux.HandleFunc("POST /v1/outbox/{tx}/cancel", s.replaceOutbox(true))
+	mux.HandleFunc("GET /v1/inbox", s.inbox)
+	mux.HandleFunc("GET /v1/inbox/{n}", s.inboxFetch)
+	mux.HandleFunc("GET /v1/events", s.events)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the token, and requests for another
+// host name so that web pages cannot reach the API by DNS rebinding.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if !isLoopbackHost(host) {
+			writeError(w, http.StatusForbidden, errors.New("forbidden host"))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
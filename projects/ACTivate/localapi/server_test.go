@@ -0,0 +1,194 @@
+package localapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"activate/contract/deployments"
+	"activate/core"
+	"activate/core/mock"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+const testToken = "test-token"
+
+// newTestServer serves the API of a service started on a mock node, with
+// the data contract on a simulated chain.
+func newTestServer(t *testing.T) (*httptest.Server, *core.Service) {
+	t.Helper()
+	chain := mock.NewChain(t)
+	node, err := mock.NewNetwork().NewNode(chain.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Transactions = chain.Transactions
+	dir := t.TempDir()
+	prefs, err := core.OpenFilePreferences(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := core.NewService(dir, prefs, nil)
+	svc.NewNode = func(opts *core.NodeOptions, password string) (core.BeeNode, error) {
+		return node, nil
+	}
+	chainID, err := chain.Client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := &core.NetworkProfile{
+		Name:        "simulated",
+		DisplayName: "Simulated chain",
+		ChainID:     chainID.Int64(),
+		RPCEndpoint: chain.IPCPath,
+		Deployment:  &deployments.Manifest{ChainID: chainID.Int64(), ContractAddress: chain.Address.Hex()},
+	}
+	if err := svc.Start(&core.NodeOptions{SwapEnable: true, RPCEndpoint: chain.IPCPath, Network: profile}, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = svc.Stop() })
+
+	srv := httptest.NewServer(New(svc, testToken).Handler())
+	t.Cleanup(srv.Close)
+	return srv, svc
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, header http.Header) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if h := header.Get("Host"); h != "" {
+		req.Host = h
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func TestAuthenticate(t *testing.T) {
+	srv, svc := newTestServer(t)
+
+	for name, header := range map[string]http.Header{
+		"no token":    {},
+		"wrong token": bearer("wrong"),
+		"basic auth":  {"Authorization": {"Basic " + testToken}},
+	} {
+		if resp := get(t, srv, "/v1/node", header); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want %d", name, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+
+	resp := get(t, srv, "/v1/node", bearer(testToken))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d with the token", resp.StatusCode)
+	}
+	var node nodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		t.Fatal(err)
+	}
+	if want := svc.Node().OverlayEthAddress().Hex(); node.Address != want {
+		t.Errorf("address = %s, want %s", node.Address, want)
+	}
+}
+
+func TestLoopbackHost(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	for host, want := range map[string]int{
+		"localhost:1640": http.StatusOK,
+		"127.0.0.1":      http.StatusOK,
+		"[::1]:1640":     http.StatusOK,
+		"attacker.test":  http.StatusForbidden,
+		"192.168.1.2":    http.StatusForbidden,
+	} {
+		header := bearer(testToken)
+		header.Set("Host", host)
+		if resp := get(t, srv, "/v1/node", header); resp.StatusCode != want {
+			t.Errorf("host %s: status %d, want %d", host, resp.StatusCode, want)
+		}
+	}
+
+	for addr, want := range map[string]error{
+		DefaultAddr:      nil,
+		"localhost:1640": nil,
+		"0.0.0.0:1640":   ErrNotLoopback,
+		"10.0.0.1:1640":  ErrNotLoopback,
+	} {
+		if err := CheckAddr(addr); err != want {
+			t.Errorf("CheckAddr(%s) = %v, want %v", addr, err, want)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	srv, svc := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = bearer(testToken)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	ref := swarm.MustParseHexAddress(strings.Repeat("c0", 32))
+	topic := core.ShareTopic(crypto.FromECDSAPub(svc.Node().PublicKey()), ref)
+	receipt, err := svc.SendData(ctx, target, make([]byte, 32), make([]byte, 32), topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event, data string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			event = v
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = v
+		}
+		if line == "" && event != "" {
+			break
+		}
+	}
+	if event != "share" {
+		t.Fatalf("event %q, want share: %v", event, scanner.Err())
+	}
+	var share shareJSON
+	if err := json.Unmarshal([]byte(data), &share); err != nil {
+		t.Fatal(err)
+	}
+	if share.TxHash != receipt.TxHash.Hex() || share.To != target.Hex() || share.Topic != topic {
+		t.Errorf("share %+v, want tx %s to %s", share, receipt.TxHash.Hex(), target.Hex())
+	}
+}
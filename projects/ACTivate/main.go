@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"activate/screens"
 
@@ -18,6 +21,16 @@ func main() {
 	w.Resize(fyne.NewSize(390, 422))
 	w.SetFixedSize(true)
 	w.SetContent(screens.Make(a, w))
+
+	// quit through fyne so the app shuts the node down before exiting
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		fmt.Printf("ACTivate received %s\n", sig)
+		fyne.Do(a.Quit)
+	}()
+
 	w.ShowAndRun()
 	tidyUp("Window Closed")
 }
@@ -0,0 +1,238 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"activate/backup"
+	"activate/core"
+	"activate/secrets"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// backupStatePrefKeys are the preferences saved in a backup next to the keys.
+var backupStatePrefKeys = []string{
+	networkPrefKey,
+	welcomeMessagePrefKey,
+	natAddressPrefKey,
+	rpcEndpointPrefKey,
+	eventsRPCPrefKey,
+	overlayAddrPrefKey,
+	selectedStampPrefKey,
+	batchPrefKey,
+	batchPolicyPrefKey,
+	groupBatchesPrefKey,
+	uploadsPrefKey,
+	outboxPrefKey,
+	eglrefPrefKey,
+	historyRefPrefKey,
+}
+
+func (i *index) backupState() map[string]string {
+	state := make(map[string]string)
+	for _, key := range backupStatePrefKeys {
+		if v := i.getPreferenceString(key); v != "" {
+			state[key] = v
+		}
+	}
+	return state
+}
+
+func (i *index) restoreState(state map[string]string) {
+	for _, key := range backupStatePrefKeys {
+		if v, ok := state[key]; ok {
+			i.setPreference(key, v)
+		}
+	}
+	if p := core.FindNetworkProfile(state[networkPrefKey]); p != nil {
+		i.nodeConfig.network = p.Name
+	}
+	if v := state[welcomeMessagePrefKey]; v != "" {
+		i.nodeConfig.welcomeMessage = v
+	}
+	if v := state[rpcEndpointPrefKey]; v != "" {
+		i.nodeConfig.rpcEndpoint = v
+	}
+	i.nodeConfig.natAddress = state[natAddressPrefKey]
+	i.nodeConfig.eventsRPCEndpoints = state[eventsRPCPrefKey]
+}
+
+func (i *index) backupButton() *widget.Button {
+	button := widget.NewButton("Export backup", func() {
+		passphraseEntry := widget.NewPasswordEntry()
+		passphraseEntry.SetPlaceHolder("Backup passphrase")
+		confirmEntry := widget.NewPasswordEntry()
+		confirmEntry.SetPlaceHolder("Confirm backup passphrase")
+		d := dialog.NewForm("Export backup", "Export", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Passphrase", passphraseEntry),
+				widget.NewFormItem("Confirm", confirmEntry),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if passphraseEntry.Text != confirmEntry.Text {
+					i.showError(fmt.Errorf("passphrases do not match"))
+					return
+				}
+				i.exportBackup(passphraseEntry.Text)
+			}, i.Window)
+		d.Show()
+	})
+	button.Importance = widget.DangerImportance
+
+	return button
+}
+
+func (i *index) exportBackup(passphrase string) {
+	if err := secrets.ValidatePassphrase(passphrase); err != nil {
+		i.showError(err)
+		return
+	}
+	password, err := i.nodePassword()
+	if err != nil {
+		i.showError(err)
+		return
+	}
+
+	i.showProgressWithMessage("Encrypting backup")
+	bundle, err := backup.New(i.nodeConfig.path, password, i.backupState())
+	if err != nil {
+		i.hideProgress()
+		i.showError(err)
+		return
+	}
+	data, err := bundle.Encrypt(passphrase)
+	i.hideProgress()
+	if err != nil {
+		i.showError(err)
+		return
+	}
+
+	saveFile := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			i.showError(err)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if _, err := writer.Write(data); err != nil {
+			i.showError(err)
+			return
+		}
+		i.logger.Log(fmt.Sprintf("Backup exported to %s", writer.URI().Name()))
+		dialog.NewInformation("Backup", "Backup exported. Keep the file and its passphrase separate.", i.Window).Show()
+	}, i.Window)
+	saveFile.SetFileName("activate-" + time.Now().Format("20060102") + backup.FileExtension)
+	saveFile.Show()
+}
+
+// showRestoreView restores the identity and state of a backup onto a fresh
+// install, before bee-lite is started for the first time.
+func (i *index) showRestoreView() fyne.CanvasObject {
+	i.intro.SetText("Restore your node from a backup file")
+	content := container.NewStack()
+
+	var backupData []byte
+	fileLabel := widget.NewLabel("No file selected")
+	openFileButton := widget.NewButton("Choose backup file", func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				i.showError(err)
+				return
+			}
+			backupData = data
+			fileLabel.SetText(reader.URI().Name())
+		}, i.Window)
+		fd.Show()
+	})
+
+	backupPassEntry := widget.NewPasswordEntry()
+	backupPassEntry.SetPlaceHolder("Backup passphrase")
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("New passphrase for this device")
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Confirm passphrase")
+
+	restoreButton := widget.NewButton("Restore", func() {
+		if backupData == nil {
+			i.showError(fmt.Errorf("choose a backup file first"))
+			return
+		}
+		if err := secrets.ValidatePassphrase(passwordEntry.Text); err != nil {
+			i.showError(err)
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			i.showError(fmt.Errorf("passphrases do not match"))
+			return
+		}
+
+		i.showProgressWithMessage("Restoring backup")
+		err := i.restoreBackup(backupData, backupPassEntry.Text, passwordEntry.Text)
+		i.hideProgress()
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to restore backup: %s", err.Error()))
+			i.showError(err)
+			return
+		}
+
+		i.logger.Log("Backup restored")
+		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Refresh()
+	})
+
+	backButton := widget.NewButton("Back", func() {
+		content.Objects = []fyne.CanvasObject{i.showPasswordView()}
+		content.Refresh()
+	})
+	backButton.Importance = widget.WarningImportance
+	restoreButton.Importance = widget.HighImportance
+	content.Objects = []fyne.CanvasObject{container.NewBorder(
+		container.NewVBox(fileLabel, openFileButton, backupPassEntry, passwordEntry, confirmEntry),
+		container.NewVBox(restoreButton, backButton), nil, nil)}
+	i.content = content
+	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
+	i.view.Refresh()
+
+	return content
+}
+
+func (i *index) restoreBackup(data []byte, backupPassphrase, passphrase string) error {
+	if i.keystoreExists() || i.hasVault() {
+		return backup.ErrKeystoreExists
+	}
+	bundle, err := backup.Decrypt(data, backupPassphrase)
+	if err != nil {
+		if errors.Is(err, secrets.ErrWrongPassphrase) {
+			return fmt.Errorf("wrong backup passphrase")
+		}
+		return err
+	}
+	if err := bundle.RestoreKeys(i.nodeConfig.path); err != nil {
+		return err
+	}
+	if err := i.createVault(passphrase, bundle.NodePassword); err != nil {
+		return err
+	}
+	i.restoreState(bundle.State)
+	return nil
+}
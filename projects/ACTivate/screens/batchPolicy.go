@@ -0,0 +1,90 @@
+package screens
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+)
+
+const (
+	batchPolicyPrefKey  = core.BatchPolicyPrefKey
+	groupBatchesPrefKey = core.GroupBatchesPrefKey
+
+	batchGroupUploads  = core.BatchGroupUploads
+	batchGroupGrantees = core.BatchGroupGrantees
+)
+
+func (i *index) setGroupBatch(group, batchHex string) {
+	if err := i.svc.SetGroupBatch(group, batchHex); err != nil {
+		i.showError(err)
+		return
+	}
+	i.logger.Log(fmt.Sprintf("Default batch for %s: %s", group, shortenHashOrAddress(batchHex)))
+}
+
+// selectBatch picks the batch for an operation of group according to the
+// batch policy and returns it with the reason it was chosen.
+func (i *index) selectBatch(group string) (*postage.StampIssuer, string, error) {
+	stamp, reason, err := i.svc.SelectBatch(group, "")
+	switch {
+	case errors.Is(err, core.ErrNoUsableBatch):
+		return nil, "", fmt.Errorf("no usable postage batch, buy one in the info card")
+	case errors.Is(err, core.ErrNoBatchSelected):
+		return nil, "", fmt.Errorf("select a postage batch in the info card")
+	}
+	return stamp, reason, err
+}
+
+// confirmBatch selects the batch for action and asks the user to confirm it,
+// with a warning if it is near full or about to expire. proceed runs on the
+// UI thread.
+func (i *index) confirmBatch(group, action string, proceed func(stamp *postage.StampIssuer)) {
+	stamp, reason, err := i.selectBatch(group)
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	go func() {
+		warnings := i.batchWarnings(stamp)
+		fyne.Do(func() {
+			message := fmt.Sprintf("%s using batch %s (%s).", action, batchOptionText(stamp), reason)
+			if len(warnings) != 0 {
+				message += "\n" + strings.Join(warnings, "\n") + "\nTop up or dilute the batch, or continue anyway?"
+			}
+			dialog.NewConfirm("Postage batch", message, func(ok bool) {
+				if ok {
+					i.logger.Log(fmt.Sprintf("stamp selected: %s", hex.EncodeToString(stamp.ID())))
+					proceed(stamp)
+				}
+			}, i.Window).Show()
+		})
+	}()
+}
+
+func (i *index) getBatchPolicySelect() *widget.Select {
+	policySelect := widget.NewSelect(core.BatchPolicies, func(s string) {
+		i.setPreference(batchPolicyPrefKey, s)
+	})
+	policySelect.SetSelected(i.svc.BatchPolicy())
+	return policySelect
+}
+
+func (i *index) groupDefaultButtons(stamp *postage.StampIssuer) fyne.CanvasObject {
+	batchHex := core.BatchHex(stamp)
+	uploadsButton := widget.NewButton("Default for uploads", func() {
+		i.setGroupBatch(batchGroupUploads, batchHex)
+	})
+	granteesButton := widget.NewButton("Default for grantees", func() {
+		i.setGroupBatch(batchGroupGrantees, batchHex)
+	})
+	return container.NewHBox(uploadsButton, granteesButton)
+}
@@ -4,42 +4,88 @@ import (
 	"context"
 	"fmt"
 
+	"activate/core"
+	"activate/secrets"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/widget"
 
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethersphere/bee/v2/pkg/api"
 )
 
 type nodeConfig struct {
 	path           string
-	password       string
 	welcomeMessage string
 	swapEnable     bool
 	natAddress     string
 	rpcEndpoint    string
+	network        string
 	isKeyStoreMem  bool
+
+	eventsRPCEndpoints string
+	rpcFallback        bool
+	autoLockMinutes    int
 }
 
 func (i *index) showPasswordView() fyne.CanvasObject {
-	i.intro.SetText("Initialise your swarm node with a strong password")
+	i.intro.SetText("Protect your swarm node with a strong passphrase or PIN")
 	content := container.NewStack()
 	passwordEntry := widget.NewPasswordEntry()
-	passwordEntry.SetPlaceHolder("Password")
+	passwordEntry.SetPlaceHolder("Passphrase")
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Confirm passphrase")
+	entries := container.NewVBox(passwordEntry, confirmEntry)
+	// installs from before the vault need the password of their keystore
+	legacyEntry := widget.NewPasswordEntry()
+	legacyEntry.SetPlaceHolder("Current node password (empty if never set)")
+	if !i.vaultUnlocked() && i.keystoreExists() && i.getPreferenceString(passwordPrefKey) == "" {
+		entries.Add(legacyEntry)
+	}
 	nextButton := widget.NewButton("Next", func() {
-		if passwordEntry.Text == "" {
-			i.showError(fmt.Errorf("password cannot be blank"))
+		if err := secrets.ValidatePassphrase(passwordEntry.Text); err != nil {
+			i.showError(err)
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			i.showError(fmt.Errorf("passphrases do not match"))
+			return
+		}
+
+		i.showProgressWithMessage("Securing the node password")
+		var err error
+		if i.vaultUnlocked() {
+			err = i.vault.ChangePassphrase(passwordEntry.Text)
+		} else {
+			err = i.createVault(passwordEntry.Text, legacyEntry.Text)
+		}
+		i.hideProgress()
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to set up the vault: %s", err.Error()))
+			i.showError(err)
 			return
 		}
 
-		i.nodeConfig.password = passwordEntry.Text
 		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
 		content.Refresh()
 	})
 	nextButton.Importance = widget.HighImportance
-	content.Objects = []fyne.CanvasObject{container.NewBorder(passwordEntry, nextButton, nil, nil)}
+	buttons := container.NewVBox(nextButton)
+	if !i.nodeConfig.isKeyStoreMem && !i.keystoreExists() && !i.hasVault() {
+		restoreButton := widget.NewButton("Restore from backup", func() {
+			content.Objects = []fyne.CanvasObject{i.showRestoreView()}
+			content.Refresh()
+		})
+		mnemonicButton := widget.NewButton("Use a recovery phrase", func() {
+			content.Objects = []fyne.CanvasObject{i.showMnemonicView()}
+			content.Refresh()
+		})
+		buttons.Add(mnemonicButton)
+		buttons.Add(restoreButton)
+	}
+	content.Objects = []fyne.CanvasObject{container.NewBorder(entries, buttons, nil, nil)}
 	i.content = content
 	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
 	i.view.Refresh()
@@ -69,7 +115,7 @@ func (i *index) showWelcomeMessageView() fyne.CanvasObject {
 		}
 
 		i.nodeConfig.welcomeMessage = welcomeMessageEntry.Text
-		content.Objects = []fyne.CanvasObject{i.showNodeModeSelectionView()}
+		content.Objects = []fyne.CanvasObject{i.showNetworkSelectionView()}
 		content.Refresh()
 	})
 
@@ -87,6 +133,35 @@ func (i *index) showWelcomeMessageView() fyne.CanvasObject {
 	return content
 }
 
+func (i *index) showNetworkSelectionView() fyne.CanvasObject {
+	i.intro.SetText("Choose the network of your node")
+	content := container.NewStack()
+	networkSelect := i.getNetworkSelect()
+	nextButton := widget.NewButton("Next", func() {
+		if networkSelect.Selected == "" {
+			i.showError(fmt.Errorf("please select the network"))
+			return
+		}
+
+		i.logger.Log(fmt.Sprintf("Using network: %s", i.networkProfile().DisplayName))
+		content.Objects = []fyne.CanvasObject{i.showNodeModeSelectionView()}
+		content.Refresh()
+	})
+
+	backButton := widget.NewButton("Back", func() {
+		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Refresh()
+	})
+	backButton.Importance = widget.WarningImportance
+	nextButton.Importance = widget.HighImportance
+	content.Objects = []fyne.CanvasObject{container.NewBorder(networkSelect, container.NewVBox(nextButton, backButton), nil, nil)}
+	i.content = content
+	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
+	i.view.Refresh()
+
+	return content
+}
+
 func (i *index) getNodeModeRadio() *widget.RadioGroup {
 	return widget.NewRadioGroup(
 		[]string{api.LightMode.String(), api.UltraLightMode.String()},
@@ -105,6 +180,11 @@ func (i *index) showNodeModeSelectionView() fyne.CanvasObject {
 	i.intro.SetText("Choose the type of your node")
 	content := container.NewStack()
 	nodeModeRadio := i.getNodeModeRadio()
+	if p := i.networkProfile(); p.UltraLightOnly {
+		nodeModeRadio.SetSelected(api.UltraLightMode.String())
+		nodeModeRadio.Disable()
+		i.logger.Log(fmt.Sprintf("%s: %s", p.DisplayName, core.ErrLightModeUnsupported))
+	}
 	nextButton := widget.NewButton("Next", func() {
 		if nodeModeRadio.Selected == "" {
 			i.showError(fmt.Errorf("please select the node mode"))
@@ -117,7 +197,7 @@ func (i *index) showNodeModeSelectionView() fyne.CanvasObject {
 	})
 
 	backButton := widget.NewButton("Back", func() {
-		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Objects = []fyne.CanvasObject{i.showNetworkSelectionView()}
 		content.Refresh()
 	})
 	backButton.Importance = widget.WarningImportance
@@ -179,19 +259,14 @@ func (i *index) showNATAddressView() fyne.CanvasObject {
 	return content
 }
 
-func (i *index) verifyRPCConnection(rpcEndpoint string) error {
+func (i *index) verifyRPCConnection(rpcEndpoint string, profile *core.NetworkProfile) error {
 	i.logger.Log(fmt.Sprintf("verifying RPC endpoint connection: %s", rpcEndpoint))
-	// test endpoint is connectable
-	eth, err := ethclient.Dial(rpcEndpoint)
-	if err != nil {
-		return fmt.Errorf("rpc endpoint is invalid or not reachable: %w", err)
-	}
-	// check connections
-	_, err = eth.ChainID(context.Background())
+	// test endpoint is connectable and serves the selected chain
+	eth, err := core.DialHealthyRPC(context.Background(), rpcEndpoint, profile.ChainID)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s: %w", profile.DisplayName, err)
 	}
-
+	eth.Close()
 	return nil
 }
 
@@ -199,13 +274,14 @@ func (i *index) showRPCView() fyne.CanvasObject {
 	i.intro.SetText("Swarm mobile needs a RPC endpoint to start")
 	content := container.NewStack()
 	rpcEntry := widget.NewEntry()
-	rpcEntry.SetPlaceHolder(setPlaceHolderText(i.nodeConfig.rpcEndpoint, defaultRPC))
+	defaultNetworkRPC := i.networkProfile().RPCEndpoint
+	rpcEntry.SetPlaceHolder(setPlaceHolderText(i.nodeConfig.rpcEndpoint, defaultNetworkRPC))
 
 	nextButton := widget.NewButton("Next", func() {
 		if i.nodeConfig.swapEnable {
 			if rpcEntry.Text == "" {
-				rpcEntry.SetText(defaultRPC)
-				i.logger.Log(fmt.Sprintf("RPC endpoint is blank, using default RPC: %s", defaultRPC))
+				rpcEntry.SetText(defaultNetworkRPC)
+				i.logger.Log(fmt.Sprintf("RPC endpoint is blank, using default RPC: %s", defaultNetworkRPC))
 			}
 			i.nodeConfig.rpcEndpoint = rpcEntry.Text
 		}
@@ -233,14 +309,10 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 	content := container.NewStack()
 	overlayAddr := i.getPreferenceString(overlayAddrPrefKey)
 
-	startButton := widget.NewButton("Start", func() {
-		if i.nodeConfig.path == "" && !i.nodeConfig.isKeyStoreMem {
-			i.showError(fmt.Errorf("invalid app storage path"))
-			return
-		}
-
-		if i.nodeConfig.password == "" {
-			i.showError(fmt.Errorf("password is empty"))
+	startNode := func() {
+		password, err := i.nodePassword()
+		if err != nil {
+			i.showError(err)
 			return
 		}
 
@@ -249,7 +321,7 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 				i.showError(fmt.Errorf("rpc endpoint is required in light mode"))
 				return
 			}
-			err := i.verifyRPCConnection(i.nodeConfig.rpcEndpoint)
+			err := i.verifyRPCConnection(i.nodeConfig.rpcEndpoint, i.networkProfile())
 			if err != nil {
 				i.logger.Log(fmt.Sprintf("rpc endpoint error: %s", err.Error()))
 				i.showError(err)
@@ -259,6 +331,14 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 				i.showError(fmt.Errorf("Overlay address is not saved, need to start in ultra-light mode first"))
 				return
 			}
+			i.showProgressWithMessage("Checking wallet balance")
+			err = i.requireFundedWallet()
+			i.hideProgress()
+			if err != nil {
+				i.logger.Log(err.Error())
+				i.showErrorWithAddr(common.HexToAddress(overlayAddr), err)
+				return
+			}
 		} else {
 			if i.nodeConfig.rpcEndpoint != "" {
 				i.showError(fmt.Errorf("rpc endpoint must be empty in ultra-light mode"))
@@ -267,22 +347,44 @@ func (i *index) showStartView(firstStart bool) fyne.CanvasObject {
 		}
 
 		i.start(i.nodeConfig.path,
-			i.nodeConfig.password,
+			password,
 			i.nodeConfig.welcomeMessage,
 			i.nodeConfig.natAddress,
 			i.nodeConfig.rpcEndpoint,
 			i.nodeConfig.swapEnable)
 		content.Refresh()
+	}
+
+	startButton := widget.NewButton("Start", func() {
+		if i.nodeConfig.path == "" && !i.nodeConfig.isKeyStoreMem {
+			i.showError(fmt.Errorf("invalid app storage path"))
+			return
+		}
+
+		if !i.vaultUnlocked() {
+			if i.hasVault() {
+				startObjects := content.Objects
+				content.Objects = []fyne.CanvasObject{i.showUnlockView(func() {
+					content.Objects = startObjects
+					content.Refresh()
+					startNode()
+				})}
+				content.Refresh()
+				return
+			}
+			// first start, or an install from before the vault existed
+			content.Objects = []fyne.CanvasObject{i.showPasswordView()}
+			content.Refresh()
+			return
+		}
+		startNode()
 	})
 
 	startButton.Importance = widget.HighImportance
 
 	bottomBox := container.NewVBox()
 	if overlayAddr != "" {
-		infoLabel := widget.NewLabel(fmt.Sprintf("Warning: cannot continue in light-mode until there is\nat least min %s (for Gas) and at least min %s available on\n address: %s",
-			NativeTokenSymbol, SwarmTokenSymbol, shortenHashOrAddress(overlayAddr)))
-		bottomBox.Add(infoLabel)
-		bottomBox.Add(i.copyButton(overlayAddr))
+		bottomBox.Add(widget.NewAccordion(widget.NewAccordionItem("Wallet", i.walletContent())))
 	}
 
 	advancedView := i.showAdvancedSettings()
@@ -348,14 +450,62 @@ func (i *index) showAdvancedSettings() fyne.CanvasObject {
 			i.logger.Log(fmt.Sprintf("failed to bind rpc endpoint: %s", err.Error()))
 		}
 	}
-	rpcEntry.SetPlaceHolder(setPlaceHolderText(i.nodeConfig.rpcEndpoint, defaultRPC))
+	rpcEntry.SetPlaceHolder(setPlaceHolderText(i.nodeConfig.rpcEndpoint, i.networkProfile().RPCEndpoint))
 	rpcEndpointItem := &widget.AccordionItem{
 		Title:  "RPC Endpoint",
 		Detail: rpcEntry,
 		Open:   false,
 	}
 
+	networkSelect := i.getNetworkSelect()
+	networkSelect.OnChanged = func(s string) {
+		p := findNetworkProfileByDisplayName(s)
+		if p == nil {
+			return
+		}
+		if i.nodeConfig.network != p.Name && i.nodeConfig.swapEnable {
+			rpcEntry.SetText(p.RPCEndpoint)
+		}
+		if p.UltraLightOnly && i.nodeConfig.swapEnable {
+			i.nodeConfig.swapEnable = false
+			i.logger.Log(fmt.Sprintf("%s: %s", p.DisplayName, core.ErrLightModeUnsupported))
+		}
+		i.nodeConfig.network = p.Name
+		rpcEntry.SetPlaceHolder(setPlaceHolderText(i.nodeConfig.rpcEndpoint, p.RPCEndpoint))
+		i.logger.Log(fmt.Sprintf("Network selected: %s (chain ID %d)", p.DisplayName, p.ChainID))
+	}
+	networkItem := &widget.AccordionItem{
+		Title:  "Network",
+		Detail: networkSelect,
+		Open:   false,
+	}
+
+	autoLockItem := &widget.AccordionItem{
+		Title:  "Auto-lock",
+		Detail: i.getAutoLockSelect(),
+		Open:   false,
+	}
+
+	eventsRPCBind := binding.BindString(&i.nodeConfig.eventsRPCEndpoints)
+	eventsRPCEntry := widget.NewEntryWithData(eventsRPCBind)
+	eventsRPCEntry.OnChanged = func(s string) {
+		err := eventsRPCBind.Set(s)
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to bind events rpc endpoints: %s", err.Error()))
+		}
+	}
+	eventsRPCEntry.SetPlaceHolder("wss://... (comma separated, optional)")
+	fallbackCheck := widget.NewCheck("Fall back to the built-in provider", func(b bool) {
+		i.nodeConfig.rpcFallback = b
+	})
+	fallbackCheck.SetChecked(i.nodeConfig.rpcFallback)
+	eventsRPCItem := &widget.AccordionItem{
+		Title:  "Events RPC",
+		Detail: container.NewVBox(eventsRPCEntry, fallbackCheck),
+		Open:   false,
+	}
+
 	return container.NewBorder(container.NewVBox(
-		widget.NewAccordion(modeSwitchItem, welcomeMsgItem, rpcEndpointItem, natAddrItem)),
+		widget.NewAccordion(networkItem, modeSwitchItem, welcomeMsgItem, rpcEndpointItem, eventsRPCItem, natAddrItem, autoLockItem)),
 		nil, nil, nil)
 }
@@ -0,0 +1,84 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// confirmFee shows the cost of a transaction at the chosen speed against the
+// balance, and calls send with a context of that speed once confirmed.
+func (i *index) confirmFee(title string, estimate func(ctx context.Context) (*core.FeeEstimate, error), send func(ctx context.Context)) {
+	feeLabel := widget.NewLabel("Estimating fees...")
+	feeLabel.Wrapping = fyne.TextWrapWord
+
+	speed := core.SpeedNormal
+	// cancelEstimate stops the estimate of the previous speed, whose result
+	// is then dropped
+	cancelEstimate := func() {}
+	speedSelect := widget.NewSelect(core.Speeds, func(s string) {
+		cancelEstimate()
+		ctx, cancel := context.WithCancel(core.WithSpeed(context.Background(), core.Speed(s)))
+		speed, cancelEstimate = core.Speed(s), cancel
+		feeLabel.SetText("Estimating fees...")
+		go func() {
+			fee, err := estimate(ctx)
+			fyne.Do(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					feeLabel.SetText(fmt.Sprintf("Fee estimate failed: %s", errorMessage(err)))
+					return
+				}
+				feeLabel.SetText(feeText(fee))
+			})
+		}()
+	})
+
+	content := container.NewVBox(
+		widget.NewForm(widget.NewFormItem("Speed", speedSelect)),
+		feeLabel,
+	)
+	d := dialog.NewCustomConfirm(title, "Send", "Cancel", content, func(ok bool) {
+		cancelEstimate()
+		if ok {
+			send(core.WithSpeed(context.Background(), speed))
+		}
+	}, i.Window)
+	speedSelect.SetSelected(string(speed))
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+}
+
+func feeText(fee *core.FeeEstimate) string {
+	text := fmt.Sprintf("Gas: %d (limit %d)\nBase fee: %s gwei, tip: %s gwei\nExpected cost: %s %s\nAt most: %s %s\nBalance: %s %s",
+		fee.GasEstimate, fee.GasLimit,
+		formatGwei(fee.BaseFee), formatGwei(fee.TipCap),
+		formatFee(fee.Expected()), NativeTokenSymbol,
+		formatFee(fee.Max()), NativeTokenSymbol,
+		formatEther(fee.Balance), NativeTokenSymbol)
+	if !fee.Affordable() {
+		text += fmt.Sprintf("\n\nThe balance does not cover the %s %s the transaction needs upfront, it will be refused.",
+			formatFee(fee.Upfront()), NativeTokenSymbol)
+	}
+	return text
+}
+
+// formatFee is formatEther with the precision of transaction fees.
+func formatFee(wei *big.Int) string {
+	f := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
+	return f.Text('f', 8)
+}
+
+func formatGwei(wei *big.Int) string {
+	f := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9))
+	return f.Text('f', 2)
+}
@@ -3,8 +3,11 @@ package screens
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 
+	"activate/core"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
@@ -15,9 +18,6 @@ import (
 func (i *index) showGranteeCard() fyne.CanvasObject {
 	var granteesData []string
 
-	eglrefString := i.getPreferenceString(eglrefPrefKey)
-	currentEglRef := swarm.ZeroAddress
-
 	statusLabel := widget.NewLabel("Loading grantees...")
 	shorten := func(s string) string {
 		if len(s) > 12 {
@@ -36,46 +36,32 @@ func (i *index) showGranteeCard() fyne.CanvasObject {
 			granteeList.Refresh()
 		}
 
-		go func(eglRefToLoad swarm.Address) {
-			var fetchedGrantees []string
-			var err error
+		go func() {
 			var newStatusText string
-
-			if !eglRefToLoad.IsZero() {
-				fetchedGrantees, err = i.bl.GetGranteeList(context.Background(), eglRefToLoad, false)
-				if err != nil {
-					i.logger.Log(fmt.Sprintf("Error fetching grantee list for EGL %s: %v", eglRefToLoad.String(), err))
-					newStatusText = "Error fetching grantee list."
-					fetchedGrantees = []string{}
-				} else {
-					if len(fetchedGrantees) == 0 {
-						newStatusText = fmt.Sprintf("No grantees in EGL: %s", shorten(eglRefToLoad.String()))
-					} else {
-						newStatusText = fmt.Sprintf("Displaying %d grantees for EGL: %s", len(fetchedGrantees), shorten(eglRefToLoad.String()))
-					}
-				}
-			} else {
+			eglRef := i.svc.GranteeListRef()
+			fetchedGrantees, err := i.svc.Grantees(context.Background())
+			switch {
+			case errors.Is(err, core.ErrNoGranteeList):
 				newStatusText = "No grantee list (EGL) loaded. Add a grantee to create one."
 				fetchedGrantees = []string{}
+			case err != nil:
+				i.logger.Log(fmt.Sprintf("Error fetching grantee list for EGL %s: %v", eglRef.String(), err))
+				newStatusText = "Error fetching grantee list."
+				fetchedGrantees = []string{}
+			case len(fetchedGrantees) == 0:
+				newStatusText = fmt.Sprintf("No grantees in EGL: %s", shorten(eglRef.String()))
+			default:
+				newStatusText = fmt.Sprintf("Displaying %d grantees for EGL: %s", len(fetchedGrantees), shorten(eglRef.String()))
 			}
 
-			// Directly update UI components and refresh from the goroutine.
-			// This is generally acceptable in Fyne v2 for many cases.
-			granteesData = fetchedGrantees
-			statusLabel.SetText(newStatusText)
-			if granteeList != nil {
-				granteeList.Refresh()
-			}
-		}(currentEglRef)
-	}
-
-	if eglrefString != "" {
-		eglrefBytes, err := hex.DecodeString(eglrefString)
-		if err == nil && len(eglrefBytes) == 64 {
-			currentEglRef = swarm.NewAddress(eglrefBytes)
-		} else {
-			i.logger.Log(fmt.Sprintf("Error decoding stored eglref '%s' (len %d) or invalid length: %v. Expected %d bytes.", eglrefString, len(eglrefBytes), err, 64))
-		}
+			fyne.Do(func() {
+				granteesData = fetchedGrantees
+				statusLabel.SetText(newStatusText)
+				if granteeList != nil {
+					granteeList.Refresh()
+				}
+			})
+		}()
 	}
 
 	granteeList = widget.NewList(
@@ -109,21 +95,10 @@ func (i *index) showGranteeCard() fyne.CanvasObject {
 		historyEntry.SetPlaceHolder("History Ref (hex, or empty for default)")
 	}
 
-	submitButton := widget.NewButton("Add Grantee / Update List", func() {
+	submitGrantee := func(stamp *postage.StampIssuer) {
 		newGranteeStr := newGranteeEntry.Text
-		if newGranteeStr == "" {
-			i.showError(fmt.Errorf("new grantee public key cannot be empty"))
-			return
-		}
 
-		stamp := i.getStamp()
-		if stamp == nil {
-			i.showError(fmt.Errorf("no usable postage stamp found"))
-			statusLabel.SetText("Error: No usable postage stamp.")
-			return
-		}
-		batchHex := hex.EncodeToString(stamp.ID())
-		i.logger.Log(fmt.Sprintf("Using stamp: %s", batchHex))
+		batchHex := core.BatchHex(stamp)
 
 		historyRefString := historyEntry.Text
 		var resolvedHistoryRef swarm.Address
@@ -143,59 +118,65 @@ func (i *index) showGranteeCard() fyne.CanvasObject {
 
 		statusLabel.SetText("Processing request...")
 
-		go func(currentEGLForOp swarm.Address, histRefForOp swarm.Address, granteeToAdd string) {
-			var newEglAddressFromAPI, newHistoryAddressFromAPI swarm.Address
-			var err error
-			var opDesc string
-
-			if currentEGLForOp.IsZero() {
-				opDesc = "CreateGrantees"
-				i.logger.Log(fmt.Sprintf("Calling %s with History: %s, Grantee: %s", opDesc, histRefForOp.String(), granteeToAdd))
-				newEglAddressFromAPI, newHistoryAddressFromAPI, err = i.bl.CreateGrantees(context.Background(), batchHex, histRefForOp, []string{granteeToAdd})
-			} else {
-				opDesc = "AddRevokeGrantees"
-				i.logger.Log(fmt.Sprintf("Calling %s with EGL: %s, History: %s, Grantee: %s", opDesc, currentEGLForOp.String(), histRefForOp.String(), granteeToAdd))
-				newEglAddressFromAPI, newHistoryAddressFromAPI, err = i.bl.AddRevokeGrantees(
-					context.Background(),
-					batchHex,
-					currentEGLForOp,
-					histRefForOp,
-					[]string{granteeToAdd},
-					[]string{},
-				)
-			}
+		go func(histRefForOp swarm.Address, granteeToAdd string) {
+			l, err := i.svc.UpdateGroup(context.Background(), batchHex, histRefForOp, []string{granteeToAdd}, nil)
+			fyne.Do(func() {
+				if err != nil {
+					i.logger.Log(err.Error())
+					i.showError(err)
+					statusLabel.SetText("Failed to update the grantee list.")
+					return
+				}
+				newGranteeEntry.SetText("")
+				historyEntry.SetText(l.HistoryRef.String())
+				loadAndRefreshGrantees() // Reload the list with the new EGL
+			})
+		}(resolvedHistoryRef, newGranteeStr)
+	}
+	submitButton := widget.NewButton("Add Grantee / Update List", func() {
+		if newGranteeEntry.Text == "" {
+			i.showError(fmt.Errorf("new grantee public key cannot be empty"))
+			return
+		}
+		i.confirmBatch(batchGroupGrantees, "Update the grantee list", submitGrantee)
+	})
 
+	shareEntry := widget.NewEntry()
+	shareEntry.SetPlaceHolder("Reference to share with the group (hex)")
+	shareButton := widget.NewButton("Share with group", func() {
+		ref, err := swarm.ParseHexAddress(shareEntry.Text)
+		if err != nil {
+			i.showError(fmt.Errorf("invalid reference: %w", err))
+			return
+		}
+		go func() {
+			targets, err := i.svc.GroupAddresses(context.Background())
 			if err != nil {
-				errMsg := fmt.Sprintf("Error in %s: %v", opDesc, err)
-				i.logger.Log(errMsg)
-				// Directly update UI from goroutine
-				i.showError(fmt.Errorf(errMsg)) // This might need to be scheduled if it manipulates UI directly beyond simple dialogs
-				statusLabel.SetText(fmt.Sprintf("Failed to %s.", opDesc))
-				statusLabel.Refresh() // Refresh the label
+				fyne.Do(func() { i.showError(err) })
 				return
 			}
-
-			newEglRefString := newEglAddressFromAPI.String()
-			newHistoryRefString := newHistoryAddressFromAPI.String()
-
-			i.logger.Log(fmt.Sprintf("Successfully %s. New EGL Ref: %s, New History Ref: %s", opDesc, newEglRefString, newHistoryRefString))
-
-			// Directly update UI components and preferences from goroutine
-			i.setPreference(eglrefPrefKey, newEglRefString)
-			i.setPreference(historyRefPrefKey, newHistoryRefString)
-
-			currentEglRef = newEglAddressFromAPI
-
-			newGranteeEntry.SetText("")
-			historyEntry.SetText(newHistoryRefString)
-
-			// Refresh relevant widgets
-			newGranteeEntry.Refresh()
-			historyEntry.Refresh()
-
-			loadAndRefreshGrantees() // Reload the list with the new EGL
-
-		}(currentEglRef, resolvedHistoryRef, newGranteeStr)
+			estimate := func(ctx context.Context) (*core.FeeEstimate, error) {
+				return i.svc.EstimateShares(ctx, targets, ref)
+			}
+			fyne.Do(func() {
+				i.confirmFee("Share with group", estimate, func(ctx context.Context) {
+					statusLabel.SetText("Sending the share to the group...")
+					go func() {
+						_, err := i.svc.SubmitShares(ctx, targets, ref)
+						fyne.Do(func() {
+							if err != nil {
+								i.logger.Log(err.Error())
+								i.showError(err)
+								statusLabel.SetText("Failed to share with the group.")
+								return
+							}
+							shareEntry.SetText("")
+							statusLabel.SetText(fmt.Sprintf("Sent to %d grantees, see Sent for the outcome.", len(targets)))
+						})
+					}()
+				})
+			})
+		}()
 	})
 
 	layout := container.NewVBox(
@@ -206,6 +187,9 @@ func (i *index) showGranteeCard() fyne.CanvasObject {
 		widget.NewLabel("History Reference:"),
 		historyEntry,
 		submitButton,
+		widget.NewLabel("Share Reference:"),
+		shareEntry,
+		shareButton,
 	)
 
 	return layout
@@ -217,43 +201,28 @@ func (i *index) createGranteeCard() fyne.CanvasObject {
 	newGranteeEntry := widget.NewEntry()
 	newGranteeEntry.SetPlaceHolder("New grantee public key")
 
-	submitButton := widget.NewButton("Create grantee list", func() {
+	createGranteeList := func(stamp *postage.StampIssuer) {
 		newGranteeStr := newGranteeEntry.Text
-		if newGranteeStr == "" {
-			i.showError(fmt.Errorf("new grantee public key cannot be empty"))
-			return
-		}
-
-		stamp := i.getStamp()
-		if stamp == nil {
-			i.showError(fmt.Errorf("no usable postage stamp found"))
-			statusLabel.SetText("Error: No usable postage stamp.")
-			return
-		}
-		batchHex := hex.EncodeToString(stamp.ID())
-		i.logger.Log(fmt.Sprintf("Using stamp: %s for AddRevokeGrantees", batchHex))
 
 		i.logger.Log("creating new grantee list as current EGL is zero address")
 
-		newEglAddressFromAPI, newHistoryAddressFromAPI, err := i.bl.CreateGrantees(context.Background(), batchHex, swarm.ZeroAddress, []string{newGranteeStr})
+		l, err := i.svc.CreateGroup(context.Background(), core.BatchHex(stamp), []string{newGranteeStr})
 		if err != nil {
-			errMsg := fmt.Sprintf("Error in CreateGrantees: %v", err)
-			i.logger.Log(errMsg)
-			i.showError(fmt.Errorf(errMsg))
+			i.logger.Log(err.Error())
+			i.showError(err)
 			statusLabel.SetText("Failed to create grantee list.")
 			return
 		}
-
-		i.logger.Log(fmt.Sprintf("New eglref: %s, New history ref: %s", newEglAddressFromAPI.String(), newHistoryAddressFromAPI.String()))
-		newEglRefString := newEglAddressFromAPI.String()
-		newHistoryRefString := newHistoryAddressFromAPI.String()
-
-		i.logger.Log(fmt.Sprintf("Successfully updated EGL. New EGL Ref: %s, New History Ref: %s", newEglRefString, newHistoryRefString))
-
-		i.setPreference(eglrefPrefKey, newEglRefString)
-		i.setPreference(historyRefPrefKey, newHistoryRefString)
+		i.logger.Log(fmt.Sprintf("Successfully created EGL. New EGL Ref: %s, New History Ref: %s", l.Ref, l.HistoryRef))
 
 		newGranteeEntry.SetText("")
+	}
+	submitButton := widget.NewButton("Create grantee list", func() {
+		if newGranteeEntry.Text == "" {
+			i.showError(fmt.Errorf("new grantee public key cannot be empty"))
+			return
+		}
+		i.confirmBatch(batchGroupGrantees, "Create the grantee list", createGranteeList)
 	})
 
 	layout := container.NewVBox(
@@ -265,12 +234,3 @@ func (i *index) createGranteeCard() fyne.CanvasObject {
 
 	return layout
 }
-
-func (i *index) getStamp() *postage.StampIssuer {
-	stamps := i.bl.GetUsableBatches()
-
-	if len(stamps) != 0 {
-		return stamps[0]
-	}
-	return nil
-}
@@ -3,65 +3,48 @@ package screens
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
+
+	"activate/core"
+	"activate/secrets"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
-	beelite "github.com/Solar-Punk-Ltd/bee-lite"
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	// "github.com/ethereum/go-ethereum/crypto" // Temporarily commented out
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethersphere/bee/v2/pkg/api"
-	"github.com/ethersphere/bee/v2/pkg/transaction" // For transaction.Service, though might be nil
 )
 
 const (
-	TestnetChainID         = 11155111
-	TestnetNetworkID       = uint64(10)
-	MainnetChainID         = 100
-	MainnetNetworkID       = uint64(1)
-	NativeTokenSymbol      = "xDAI"
-	SwarmTokenSymbol       = "xBZZ"
-	defaultRPC             = "wss://gnosis-mainnet.g.alchemy.com/v2/YtM4LIorMJrGNRWkvAOFWSKTDzhNsCMz"
-	defaultTestRPC         = "https://eth-sepolia.g.alchemy.com/v2/atcICv4EFi9hXKew1D4LvnH36cm5-96S"
-	defaultWelcomeMsg      = "Welcome from ACTivate!"
-	defaultPassword        = "defaultpassword"
-	defaultNatAddress      = ""
-	defaultSwapEnable      = true
-	dataContractAddressHex = "0x242A2174fa8d8586a784aBdB4fF03C3181E96bee"
-	infoLogLevel           = "3"
-	defaultDepth           = "21"
-	defaultAmount          = "500000000"
-	defaultImmutable       = true
-	passwordPrefKey        = "password"
-	welcomeMessagePrefKey  = "welcomeMessage"
-	swapEnablePrefKey      = "swapEnable"
-	natAddressPrefKey      = "natAddress"
-	rpcEndpointPrefKey     = "rpcEndpoint"
-	selectedStampPrefKey   = "selected_stamp"
-	batchPrefKey           = "batch"
-	uploadsPrefKey         = "uploads"
-	overlayAddrPrefKey     = "overlayAddress"
-	eglrefPrefKey          = "eglref"
-	historyRefPrefKey      = "historyRef"
-)
-
-var (
-	MainnetBootnodes = []string{
-		"/dnsaddr/mainnet.ethswarm.org",
-	}
-
-	TestnetBootnodes = []string{
-		"/dnsaddr/testnet.ethswarm.org",
-	}
+	NativeTokenSymbol     = "xDAI"
+	SwarmTokenSymbol      = "xBZZ"
+	defaultWelcomeMsg     = core.DefaultWelcomeMessage
+	defaultNatAddress     = ""
+	defaultSwapEnable     = true
+	defaultCapacity       = "1"  // GiB
+	defaultDuration       = "30" // days
+	defaultImmutable      = true
+	passwordPrefKey       = "password" // legacy, the password now lives in the vault
+	welcomeMessagePrefKey = core.WelcomeMessagePrefKey
+	swapEnablePrefKey     = core.SwapEnablePrefKey
+	natAddressPrefKey     = core.NatAddressPrefKey
+	rpcEndpointPrefKey    = core.RPCEndpointPrefKey
+	selectedStampPrefKey  = core.SelectedStampPrefKey
+	batchPrefKey          = core.BatchPrefKey
+	uploadsPrefKey        = core.UploadsPrefKey
+	overlayAddrPrefKey    = core.OverlayAddrPrefKey
+	eglrefPrefKey         = core.EglrefPrefKey
+	historyRefPrefKey     = core.HistoryRefPrefKey
 )
 
 type logger struct{}
@@ -82,49 +65,62 @@ type index struct {
 	content    *fyne.Container
 	intro      *widget.Label
 	progress   dialog.Dialog
-	bl         *beelite.Beelite
+	bl         core.BeeNode
 	logger     *logger
 	nodeConfig *nodeConfig
 
-	ethClient            *ethclient.Client
-	contractSvc          DataContractInterface
-	dataContractABI      abi.ABI // Store the parsed ABI here
+	ctx                  context.Context // cancelled on shutdown
+	cancel               context.CancelFunc
+	shutdownOnce         sync.Once
+	stopStatus           context.CancelFunc
+	stopAPI              context.CancelFunc
+	svc                  *core.Service
+	vault                *secrets.Vault
+	autoLockTimer        *time.Timer
+	stampsMu             sync.Mutex
+	stamps               *stampsContract
+	batchTTLs            sync.Map // batch ID hex -> time.Duration
 	eventLogSubscription ethereum.Subscription
 	eventMessageLabel    *widget.Label
 }
 
-func (i *index) initContract(txService transaction.Service) {
-	rpcEndpoint := defaultRPC
-	var err error
-	i.ethClient, err = ethclient.DialContext(context.Background(), rpcEndpoint)
-	if err != nil {
-		i.logger.Log(fmt.Sprintf("Failed to connect to Ethereum client via %s: %v", rpcEndpoint, err))
-	}
-
-	i.dataContractABI, err = ParseContractABI()
-	if err != nil {
-		i.logger.Log(fmt.Sprintf("Failed to parse data contract ABI JSON: %v", err))
-		i.showError(err)
-	}
+// initContract sets up the event label and connects to the data contract in
+// the background, the contract events are subscribed to once it is connected.
+// It must run on the UI thread.
+func (i *index) initContract() {
+	i.eventMessageLabel = widget.NewLabel("Initializing event listener...")
+	i.eventMessageLabel.Wrapping = fyne.TextWrapWord
+	i.eventMessageLabel.Alignment = fyne.TextAlignCenter
 
-	dataContractAddr := common.HexToAddress(dataContractAddressHex)
+	go func() {
+		err := i.svc.ConnectContract(context.Background())
+		fyne.Do(func() {
+			i.contractConnected(err)
+			i.setupDataContractSubscription()
+		})
+	}()
+}
 
-	if i.dataContractABI.Events != nil { // Check if ABI was parsed and has events
-		i.contractSvc = NewDataContract(
-			i.bl.OverlayEthAddress(),
-			dataContractAddr,
-			i.dataContractABI,
-			txService,
-			true, // setGasLimit
-		)
-	} else {
-		i.logger.Log("Data contract ABI not parsed or no events found, contractSvc not initialized.")
+// contractConnected reports the result of connecting to the data contract. It
+// must run on the UI thread.
+func (i *index) contractConnected(err error) {
+	switch {
+	case errors.Is(err, core.ErrNoDataContract):
+		i.logger.Log(fmt.Sprintf("No data contract deployed on %s, contractSvc not initialized.", i.networkProfile().DisplayName))
+	case err != nil:
+		i.logger.Log(fmt.Sprintf("Failed to connect to the data contract: %v", err))
+		i.showError(err)
 	}
+}
 
-	// Initialize UI elements for events
-	i.eventMessageLabel = widget.NewLabel("Initializing event listener...")
-	i.eventMessageLabel.Wrapping = fyne.TextWrapWord
-	i.eventMessageLabel.Alignment = fyne.TextAlignCenter
+// setEventMessage shows text in the event label. It can be called from any
+// goroutine.
+func (i *index) setEventMessage(text string) {
+	fyne.Do(func() {
+		if i.eventMessageLabel != nil {
+			i.eventMessageLabel.SetText(text)
+		}
+	})
 }
 
 func Make(a fyne.App, w fyne.Window) fyne.CanvasObject {
@@ -138,6 +134,9 @@ func Make(a fyne.App, w fyne.Window) fyne.CanvasObject {
 	i.intro.Wrapping = fyne.TextWrapWord
 	i.printAppInfo()
 
+	i.ctx, i.cancel = context.WithCancel(context.Background())
+	i.setupLifecycle()
+
 	i.nodeConfig.isKeyStoreMem = a.Driver().Device().IsBrowser()
 	if i.nodeConfig.isKeyStoreMem {
 		i.logger.Log("Running in browser, using in-memory keystore")
@@ -146,13 +145,36 @@ func Make(a fyne.App, w fyne.Window) fyne.CanvasObject {
 		i.logger.Log("App datadir path: " + i.nodeConfig.path)
 	}
 
+	i.finishWipe()
+	i.svc = core.NewService(i.nodeConfig.path, i.preferences(), i.logger)
+	i.svc.OnOutboxUpdate = i.outboxUpdated
+
 	i.nodeConfig.welcomeMessage = defaultWelcomeMsg
-	i.nodeConfig.password = defaultPassword
 	i.nodeConfig.natAddress = defaultNatAddress
-	i.nodeConfig.rpcEndpoint = defaultRPC
 	i.nodeConfig.swapEnable = defaultSwapEnable
-
-	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, container.NewStack(i.showStartView(false)))
+	i.logDeployments()
+	i.nodeConfig.network = i.getPreferenceString(networkPrefKey)
+	if core.FindNetworkProfile(i.nodeConfig.network) == nil {
+		i.nodeConfig.network = core.DefaultNetwork
+	}
+	i.nodeConfig.rpcEndpoint = i.networkProfile().RPCEndpoint
+	i.nodeConfig.eventsRPCEndpoints = i.getPreferenceString(eventsRPCPrefKey)
+	i.nodeConfig.rpcFallback = i.getPreferenceBoolWithFallback(rpcFallbackPrefKey, true)
+	i.nodeConfig.autoLockMinutes = i.getPreferenceIntWithFallback(autoLockPrefKey, defaultAutoLockMinutes)
+	i.setupAutoLock()
+
+	firstView := container.NewStack()
+	if i.hasVault() {
+		// returning user, the node password has to be unlocked first
+		i.intro.SetText("Welcome back")
+		firstView.Objects = []fyne.CanvasObject{i.showUnlockView(func() {
+			firstView.Objects = []fyne.CanvasObject{i.showStartView(false)}
+			firstView.Refresh()
+		})}
+	} else {
+		firstView.Objects = []fyne.CanvasObject{i.showStartView(false)}
+	}
+	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, firstView)
 	i.view.Refresh()
 	return i.view
 }
@@ -175,69 +197,38 @@ func (i *index) start(path, password, welcomeMessage, natAddress, rpcEndpoint st
 		return
 	}
 
-	i.initContract(i.bl.TransactionService())
-
-	if swapEnable {
-		if i.bl.BeeNodeMode() != api.LightMode {
-			i.showError(fmt.Errorf("swap is enabled but the current node mode is: %s", i.bl.BeeNodeMode()))
-			return
-		}
-	} else if i.bl.BeeNodeMode() != api.UltraLightMode {
-		i.showError(fmt.Errorf("swap disabled but the current node mode is: %s", i.bl.BeeNodeMode()))
-		return
-	}
-
-	i.setPreference(welcomeMessagePrefKey, welcomeMessage)
-	i.setPreference(swapEnablePrefKey, swapEnable)
-	i.setPreference(natAddressPrefKey, natAddress)
-	i.setPreference(rpcEndpointPrefKey, rpcEndpoint)
+	i.initContract()
+	i.recheckOutbox()
+	i.startLocalAPI()
+	i.nodeConfig.swapEnable = swapEnable
+	i.nodeConfig.rpcEndpoint = rpcEndpoint
+	i.setPreference(autoLockPrefKey, i.nodeConfig.autoLockMinutes)
 	i.loadMenuView()
 	i.intro.SetText("")
 	i.intro.Hide()
 }
 
+// initSwarm starts the node through the core service, which saves the node
+// settings once it runs in the requested mode.
 func (i *index) initSwarm(dataDir, welcomeMessage, password, natAddress, rpcEndpoint string, swapEnable bool) error {
-	i.logger.Log(welcomeMessage)
-
-	// isMainnet := rpcEndpoint == defaultRPC
-	isMainnet := true
-	networkID := MainnetNetworkID
-	if !isMainnet {
-		networkID = TestnetNetworkID
-	}
-
-	lo := &beelite.LiteOptions{
-		FullNodeMode:             false,
-		BootnodeMode:             false,
-		Bootnodes:                MainnetBootnodes,
-		DataDir:                  dataDir,
-		WelcomeMessage:           welcomeMessage,
-		BlockchainRpcEndpoint:    rpcEndpoint,
-		SwapInitialDeposit:       "0",
-		PaymentThreshold:         "100000000",
-		SwapEnable:               swapEnable,
-		ChequebookEnable:         true,
-		UsePostageSnapshot:       false,
-		Mainnet:                  isMainnet,
-		NetworkID:                networkID,
-		NATAddr:                  natAddress,
-		CacheCapacity:            32 * 1024 * 1024,
-		DBOpenFilesLimit:         50,
-		DBWriteBufferSize:        32 * 1024 * 1024,
-		DBBlockCacheCapacity:     32 * 1024 * 1024,
-		DBDisableSeeksCompaction: false,
-		RetrievalCaching:         true,
-	}
+	err := i.svc.Start(nodeOptions(i.nodeConfig, dataDir, welcomeMessage, natAddress, rpcEndpoint, swapEnable), password)
+	i.bl = i.svc.Node()
+	return err
+}
 
-	bl, err := beelite.Start(lo, password, infoLogLevel)
-	if err != nil {
-		return err
+// nodeOptions returns the options of a node started in the given mode, the
+// network and RPC settings are taken from cfg.
+func nodeOptions(cfg *nodeConfig, dataDir, welcomeMessage, natAddress, rpcEndpoint string, swapEnable bool) *core.NodeOptions {
+	return &core.NodeOptions{
+		DataDir:            dataDir,
+		WelcomeMessage:     welcomeMessage,
+		NATAddress:         natAddress,
+		RPCEndpoint:        rpcEndpoint,
+		SwapEnable:         swapEnable,
+		Network:            cfg.networkProfile(),
+		EventsRPCEndpoints: cfg.eventsRPCEndpoints,
+		RPCFallback:        cfg.rpcFallback,
 	}
-
-	i.setPreference(passwordPrefKey, password)
-	i.setPreference(overlayAddrPrefKey, bl.OverlayEthAddress().String())
-	i.bl = bl
-	return err
 }
 
 func (i *index) loadMenuView() {
@@ -245,7 +236,8 @@ func (i *index) loadMenuView() {
 	ultraLightMode := i.bl.BeeNodeMode() == api.UltraLightMode
 	infoCard := i.showInfoCard(ultraLightMode)
 
-	menuContent := container.NewVBox(infoCard)
+	healthIndicator, statusCard := i.showStatusDashboard()
+	menuContent := container.NewVBox(healthIndicator, infoCard, statusCard, i.showWalletCard())
 
 	granteeList := i.showGranteeCard()
 	menuContent.Add(granteeList)
@@ -254,9 +246,19 @@ func (i *index) loadMenuView() {
 	sendTxButton := i.sendTransactionButton()
 	menuContent.Add(sendTxButton)
 
+	menuContent.Add(i.outboxButton(fyne.NewSize(350, 200)))
+
 	downloadCard := i.showDownloadCard()
 	menuContent.Add(downloadCard)
 
+	menuContent.Add(i.modeSwitchButton())
+	if !i.nodeConfig.isKeyStoreMem {
+		menuContent.Add(i.showLocalAPICard())
+		menuContent.Add(i.lockButton())
+		menuContent.Add(i.duressButton())
+	}
+	menuContent.Add(i.wipeButton())
+
 	if i.eventMessageLabel != nil {
 		menuContent.Add(i.eventMessageLabel)
 	} else {
@@ -265,8 +267,6 @@ func (i *index) loadMenuView() {
 		menuContent.Add(widget.NewLabel("Event display not initialized."))
 	}
 
-	i.setupDataContractSubscription()
-
 	i.content.Objects = []fyne.CanvasObject{container.NewBorder(
 		nil,
 		nil,
@@ -278,6 +278,11 @@ func (i *index) loadMenuView() {
 }
 
 func (i *index) setupDataContractSubscription() {
+	if i.svc.Contract() == nil {
+		i.setEventMessage("No data contract on this network, event listener disabled.")
+		return
+	}
+
 	if i.eventLogSubscription != nil {
 		i.eventLogSubscription.Unsubscribe() // Unsubscribe from previous if any
 	}
@@ -285,35 +290,33 @@ func (i *index) setupDataContractSubscription() {
 	logs := make(chan types.Log)
 	var err error
 
-	// Use a new context for the subscription goroutine, or manage it with the app's lifecycle
-	subCtx, cancelSubCtx := context.WithCancel(context.Background())
-	i.Window.SetOnClosed(func() { // Ensure cancellation when window closes
-		cancelSubCtx()
-	})
+	// The subscription stops with the window.
+	subCtx, cancelSubCtx := context.WithCancel(i.ctx)
 
-	i.eventLogSubscription, err = i.contractSvc.SubscribeDataSentToTarget(subCtx, i.ethClient, logs)
+	i.eventLogSubscription, err = i.svc.SubscribeShares(subCtx, logs)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to subscribe to DataSentToTarget: %v", err)
 		i.logger.Log(errMsg)
-		if i.eventMessageLabel != nil {
-			i.eventMessageLabel.SetText(errMsg)
-		}
+		i.setEventMessage(errMsg)
 		cancelSubCtx() // Cancel context if subscription fails
+		go i.failoverContractRPC()
 		return
 	}
 
 	i.logger.Log("Successfully subscribed to DataSentToTarget events.")
-	if i.eventMessageLabel != nil {
-		i.eventMessageLabel.SetText("Subscribed. Waiting for 'DataSentToTarget' events...")
-	}
+	i.setEventMessage("Subscribed. Waiting for 'DataSentToTarget' events...")
 
 	go func() {
+		resubscribe := false
 		defer func() {
 			if i.eventLogSubscription != nil {
 				i.eventLogSubscription.Unsubscribe()
 			}
 			cancelSubCtx() // Ensure context is cancelled when goroutine exits
 			i.logger.Log("Event listener goroutine stopped.")
+			if resubscribe {
+				i.failoverContractRPC()
+			}
 		}()
 
 		for {
@@ -324,243 +327,44 @@ func (i *index) setupDataContractSubscription() {
 			case err := <-i.eventLogSubscription.Err():
 				errMsg := fmt.Sprintf("Event subscription error: %v", err)
 				i.logger.Log(errMsg)
-				if i.eventMessageLabel != nil {
-					i.eventMessageLabel.SetText("Subscription error. Check logs.")
-				}
-				// Depending on the error, you might want to attempt to resubscribe.
-				// For now, we stop listening on error.
+				i.setEventMessage("Subscription error. Check logs.")
+				// Resubscribe through the next healthy endpoint, if any.
+				resubscribe = true
 				return
-			case vLog := <-logs:
-				i.logger.Log(fmt.Sprintf("Received log: Block %d, TxHash %s, Topics %d, Data %d bytes", vLog.BlockNumber, vLog.TxHash.Hex(), len(vLog.Topics), len(vLog.Data)))
-
-				eventName := "DataSentToTarget"
-				eventAbi, ok := i.dataContractABI.Events[eventName]
+			case vLog, ok := <-logs:
 				if !ok {
-					i.logger.Log(fmt.Sprintf("Event %s not found in ABI. Cannot parse.", eventName))
-					continue
+					i.logger.Log("Event log channel closed.")
+					resubscribe = subCtx.Err() == nil
+					return
 				}
+				i.logger.Log(fmt.Sprintf("Received log: Block %d, TxHash %s, Topics %d, Data %d bytes", vLog.BlockNumber, vLog.TxHash.Hex(), len(vLog.Topics), len(vLog.Data)))
 
-				// Check if this log is indeed for DataSentToTarget based on Topic[0]
-				if len(vLog.Topics) == 0 || vLog.Topics[0] != eventAbi.ID {
-					i.logger.Log(fmt.Sprintf("Received log does not match %s event signature. Skipping. Log Topic0: %s, Expected: %s", eventName, vLog.Topics[0].Hex(), eventAbi.ID.Hex()))
+				share, err := i.svc.ReceiveShare(vLog)
+				if err != nil {
+					i.logger.Log(fmt.Sprintf("Skipping log: %v", err))
 					continue
 				}
-				i.logger.Log(fmt.Sprintf("Processing '%s' event...", eventName))
-
-				var targetAddr common.Address
-				var ownerBytes []byte
-				var actRefBytes []byte
-				var topicString string
-
-				// Unpack indexed fields from Topics
-				topicIdx := 1 // Topics[0] is the event signature itself
-				for _, input := range eventAbi.Inputs {
-					if input.Indexed {
-						if topicIdx < len(vLog.Topics) {
-							if input.Name == "target" { // Assuming 'target' is the name in ABI
-								targetAddr = common.BytesToAddress(vLog.Topics[topicIdx].Bytes())
-							}
-							// Add other indexed fields here if any, by checking input.Name or type
-							topicIdx++
-						} else {
-							i.logger.Log(fmt.Sprintf("Warning: Mismatch count for indexed ABI inputs and log topics for event %s.", eventName))
-							break
-						}
-					}
-				}
 
-				// Prepare to unpack non-indexed fields from Data
-				var nonIndexedArgs abi.Arguments
-				for _, input := range eventAbi.Inputs {
-					if !input.Indexed {
-						nonIndexedArgs = append(nonIndexedArgs, input)
-					}
+				parsedMsg := fmt.Sprintf("'DataSentToTarget' Event! Block: %d.", share.BlockNumber)
+				if share.From != (common.Address{}) {
+					parsedMsg += fmt.Sprintf(" From: %s.", share.From.Hex())
 				}
-
-				if len(nonIndexedArgs) > 0 {
-					unpackedData, err := nonIndexedArgs.Unpack(vLog.Data)
-					if err != nil {
-						i.logger.Log(fmt.Sprintf("Failed to unpack non-indexed data for event %s: %v", eventName, err))
-					} else {
-						// Debug: Log the unpacked data structure
-						i.logger.Log(fmt.Sprintf("Unpacked data count: %d", len(unpackedData)))
-						for idx, data := range unpackedData {
-							i.logger.Log(fmt.Sprintf("Unpacked data[%d]: %T = %v", idx, data, data))
-						}
-
-						// Debug: Log the argument names and types
-						for idx, arg := range nonIndexedArgs {
-							i.logger.Log(fmt.Sprintf("Arg[%d]: Name='%s', Type='%s'", idx, arg.Name, arg.Type.String()))
-						}
-
-						// Assign to variables based on the order of non-indexed args in ABI
-						// The ABI shows: owner (bytes32), actref (bytes32), topic (string)
-						currentUnpackedIdx := 0
-						for _, arg := range nonIndexedArgs {
-							if currentUnpackedIdx >= len(unpackedData) {
-								break
-							}
-							switch arg.Name { // Match the actual ABI field names
-							case "owner":
-								if val, ok := unpackedData[currentUnpackedIdx].([32]byte); ok {
-									ownerBytes = val[:]
-								} else if val, ok := unpackedData[currentUnpackedIdx].([]byte); ok {
-									ownerBytes = val
-								}
-							case "actref": // Note: ABI uses "actref" not "actRef"
-								if val, ok := unpackedData[currentUnpackedIdx].([32]byte); ok {
-									actRefBytes = val[:]
-								} else if val, ok := unpackedData[currentUnpackedIdx].([]byte); ok {
-									actRefBytes = val
-								}
-							case "topic":
-								if val, ok := unpackedData[currentUnpackedIdx].(string); ok {
-									topicString = val
-								}
-							}
-							currentUnpackedIdx++
-						}
-					}
-				}
-
-				parsedMsg := fmt.Sprintf("'DataSentToTarget' Event! Block: %d.", vLog.BlockNumber)
-				if targetAddr != (common.Address{}) {
-					parsedMsg += fmt.Sprintf(" Target: %s.", targetAddr.Hex())
+				if share.To != (common.Address{}) {
+					parsedMsg += fmt.Sprintf(" To: %s.", share.To.Hex())
 				}
-				if len(ownerBytes) > 0 {
-					parsedMsg += fmt.Sprintf(" Owner: 0x%x.", ownerBytes)
+				if len(share.Owner) > 0 {
+					parsedMsg += fmt.Sprintf(" Owner: 0x%x.", share.Owner)
 				}
-				if len(actRefBytes) > 0 {
-					parsedMsg += fmt.Sprintf(" ActRef: 0x%x.", actRefBytes)
+				if len(share.ActRef) > 0 {
+					parsedMsg += fmt.Sprintf(" ActRef: 0x%x.", share.ActRef)
 				}
-				if topicString != "" {
-					parsedMsg += fmt.Sprintf(" Topic: '%s'.", topicString)
+				if share.Topic != "" {
+					parsedMsg += fmt.Sprintf(" Topic: '%s'.", share.Topic)
 				}
 
 				i.logger.Log("Formatted event message: " + parsedMsg)
-				if i.eventMessageLabel != nil {
-					i.eventMessageLabel.SetText(parsedMsg)
-				}
-
-				// TODO: Temporarily commented out decryption - uncomment when needed
-				/*
-					// Decrypt the data before storing it using a placeholder private key
-					encryptionUtils := &EncryptionUtils{}
-
-					// Placeholder private key (hex encoded) - replace with actual private key in production
-					placeholderPrivateKeyHex := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
-
-					// Parse the private key
-					privateKeyBytes, err := hex.DecodeString(placeholderPrivateKeyHex)
-					if err != nil {
-						i.logger.Log(fmt.Sprintf("Failed to decode placeholder private key: %v", err))
-					} else {
-						// Convert to ECDSA private key
-						privateKey, err := crypto.ToECDSA(privateKeyBytes)
-						if err != nil {
-							i.logger.Log(fmt.Sprintf("Failed to convert to ECDSA private key: %v", err))
-						} else {
-							// Print the corresponding public key
-							publicKeyBytes := crypto.FromECDSAPub(&privateKey.PublicKey)
-							publicKeyHex := hex.EncodeToString(publicKeyBytes)
-							i.logger.Log(fmt.Sprintf("Placeholder private key's public key: %s", publicKeyHex))
-
-							// Attempt to decrypt the data
-							var decryptedOwnerBytes []byte
-							var decryptedActRefBytes []byte
-							var decryptedTopicString string
-
-							// Decrypt owner bytes
-							if len(ownerBytes) > 0 {
-								decryptedOwner, err := encryptionUtils.DecryptData(ownerBytes, privateKey)
-								if err != nil {
-									i.logger.Log(fmt.Sprintf("Failed to decrypt owner data, using original: %v", err))
-									decryptedOwnerBytes = ownerBytes
-								} else {
-									decryptedOwnerBytes = decryptedOwner
-									i.logger.Log("Successfully decrypted owner data")
-								}
-							} else {
-								decryptedOwnerBytes = ownerBytes
-							}
-
-							// Decrypt actRef bytes
-							if len(actRefBytes) > 0 {
-								decryptedActRef, err := encryptionUtils.DecryptData(actRefBytes, privateKey)
-								if err != nil {
-									i.logger.Log(fmt.Sprintf("Failed to decrypt actRef data, using original: %v", err))
-									decryptedActRefBytes = actRefBytes
-								} else {
-									decryptedActRefBytes = decryptedActRef
-									i.logger.Log("Successfully decrypted actRef data")
-								}
-							} else {
-								decryptedActRefBytes = actRefBytes
-							}
-
-							// Decrypt topic string
-							if topicString != "" {
-								decryptedTopic, err := encryptionUtils.DecryptData([]byte(topicString), privateKey)
-								if err != nil {
-									i.logger.Log(fmt.Sprintf("Failed to decrypt topic data, using original: %v", err))
-									decryptedTopicString = topicString
-								} else {
-									decryptedTopicString = string(decryptedTopic)
-									i.logger.Log("Successfully decrypted topic data")
-								}
-							} else {
-								decryptedTopicString = topicString
-							}
-
-							// Use decrypted data for storage
-							ownerBytes = decryptedOwnerBytes
-							actRefBytes = decryptedActRefBytes
-							topicString = decryptedTopicString
-						}
-					}
-				*/
-
-				i.logger.Log("Using raw event data without decryption") // Parse the modified topic data (publicKey + 32-byte hex string)
-				if len(topicString) >= 194 {                            // 130 chars (public key) + 64 chars (32-byte hex) = 194 chars
-					// Extract the public key (first 130 characters if it starts with 04, otherwise first 128)
-					var extractedPublicKey string
-					var extracted32ByteHex string
-
-					if len(topicString) >= 130 && topicString[:2] == "04" {
-						// Uncompressed public key format (130 chars)
-						extractedPublicKey = topicString[:130]
-						if len(topicString) >= 194 {
-							extracted32ByteHex = topicString[130:194]
-						} else {
-							extracted32ByteHex = topicString[130:]
-						}
-					} else if len(topicString) >= 128 {
-						// Compressed public key or other format (128 chars)
-						extractedPublicKey = topicString[:128]
-						if len(topicString) >= 192 {
-							extracted32ByteHex = topicString[128:192]
-						} else {
-							extracted32ByteHex = topicString[128:]
-						}
-					}
-
-					i.logger.Log(fmt.Sprintf("Extracted from topic - PublicKey: %s", extractedPublicKey))
-					i.logger.Log(fmt.Sprintf("Extracted from topic - 32ByteHex: %s", extracted32ByteHex))
-
-					// Store both parts separately
-					i.setPreference("eventPublicKey", extractedPublicKey)
-					i.setPreference("event32ByteHex", extracted32ByteHex)
-				} else {
-					i.logger.Log(fmt.Sprintf("Topic string too short (%d chars) to contain publicKey + 32-byte hex", len(topicString)))
-				}
-
-				// use setPreference to store the owner, actRef, and topic
-				i.setPreference("eventOwner", hex.EncodeToString(ownerBytes))
-				i.setPreference("eventActRef", hex.EncodeToString(actRefBytes))
-				i.setPreference("eventTopic", topicString)
-				i.logger.Log("Stored owner, actRef, and topic in preferences.")
+				i.setEventMessage(parsedMsg)
 				i.logger.Log("Event processing complete.")
-
 			}
 		}
 	}()
@@ -568,7 +372,7 @@ func (i *index) setupDataContractSubscription() {
 
 func (i *index) sendTransactionButton() *widget.Button {
 	button := widget.NewButton("Send Transaction", func() {
-		if i.contractSvc == nil {
+		if i.svc.Contract() == nil {
 			i.showError(fmt.Errorf("contract service not initialized"))
 			return
 		}
@@ -657,9 +461,6 @@ func (i *index) sendTransactionButton() *widget.Button {
 				return
 			}
 
-			// Show progress dialog
-			i.showProgressWithMessage("Processing and sending transaction...")
-
 			target := common.HexToAddress(targetEntry.Text)
 			ownerAddr := common.HexToAddress(ownerEntry.Text) // Owner as address
 			actRefData = actRefEntry.Text                     // ACT ref as hex string
@@ -676,8 +477,6 @@ func (i *index) sendTransactionButton() *widget.Button {
 			}
 
 			go func() {
-				defer i.hideProgress()
-
 				var owner, actRef []byte
 				var topic string
 				var encryptionInfo string
@@ -739,19 +538,33 @@ func (i *index) sendTransactionButton() *widget.Button {
 				i.logger.Log("Transaction data sent without encryption")
 				// }
 
-				ctx := context.Background()
-				receipt, err := i.contractSvc.SendDataToTarget(ctx, target, owner, actRef, topic)
-				if err != nil {
-					i.showError(fmt.Errorf("failed to send transaction: %w", err))
-					return
+				estimate := func(ctx context.Context) (*core.FeeEstimate, error) {
+					return i.svc.EstimateData(ctx, []common.Address{target}, owner, actRef, topic)
 				}
+				fyne.Do(func() {
+					i.confirmFee("Send Transaction", estimate, func(ctx context.Context) {
+						// Show progress dialog
+						i.showProgressWithMessage("Processing and sending transaction...")
+						go func() {
+							txHash, err := i.svc.SubmitData(ctx, []common.Address{target}, owner, actRef, topic)
+							fyne.Do(func() {
+								i.hideProgress()
+								if err != nil {
+									i.showError(fmt.Errorf("failed to send transaction: %w", err))
+									return
+								}
 
-				// Show success message with transaction hash and encryption info
-				successMsg := fmt.Sprintf("Transaction sent successfully!\nTransaction Hash: %s\nBlock Number: %d\n\nEncryption: %s",
-					receipt.TxHash.Hex(), receipt.BlockNumber.Uint64(), encryptionInfo)
-
-				dialog.ShowInformation("Transaction Success", successMsg, i.Window)
-				i.logger.Log(fmt.Sprintf("Transaction successful: %s (%s)", receipt.TxHash.Hex(), encryptionInfo))
+								// Show success message with transaction hash and encryption info, the
+								// outcome is notified once the transaction is mined
+								successMsg := fmt.Sprintf("Transaction sent!\nTransaction Hash: %s\n\nEncryption: %s\n\nFollow it, speed it up or cancel it under Sent.",
+									txHash.Hex(), encryptionInfo)
+
+								dialog.ShowInformation("Transaction Sent", successMsg, i.Window)
+								i.logger.Log(fmt.Sprintf("Transaction sent: %s (%s)", txHash.Hex(), encryptionInfo))
+							})
+						}()
+					})
+				})
 			}()
 		}, i.Window)
 
@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -10,7 +11,6 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
-	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 	"github.com/ethersphere/bee/v2/pkg/crypto"
 )
@@ -18,7 +18,6 @@ import (
 func (i *index) showInfoCard(ultraLightMode bool) *widget.Card {
 	addressContent := i.addressContent()
 	pubkeyContent := i.pubkeyContent()
-	walletDataButton := i.walletDataButton()
 	infoContent := container.NewVBox(addressContent)
 	if !ultraLightMode {
 		batchRadio := i.batchRadio()
@@ -26,38 +25,11 @@ func (i *index) showInfoCard(ultraLightMode bool) *widget.Card {
 		buyBatchButton := i.buyBatchButton(batchRadio)
 		infoContent = container.NewVBox(addressContent, pubkeyContent, stampsContent, buyBatchButton)
 	}
-	infoContent.Add(walletDataButton)
-
-	infoCard := widget.NewCard("Info",
-		fmt.Sprintf("Connected with %d peers", i.bl.ConnectedPeerCount()), infoContent)
-
-	// auto reload
-	go func() {
-		for {
-			time.Sleep(time.Second * 60)
-			if i.bl != nil && infoCard != nil && infoCard.Visible() {
-				infoCard.SetSubTitle(fmt.Sprintf("Connected with %d peers", i.bl.ConnectedPeerCount()))
-			}
-		}
-	}()
-
-	return infoCard
-}
-
-func (i *index) walletDataButton() *widget.Button {
-	button := widget.NewButton("Read wallet data ", func() {
-		key, err := i.readAppData("/keys/swarm.key")
-		if err != nil {
-			i.showError(fmt.Errorf("failed to read swarm.key: %s", err.Error()))
-			return
-		}
-
-		d := dialog.NewCustomConfirm("Wallet data", "Ok", "Cancel", i.copyDialog("Do not share this with anyone!\nCopy and save your key file.", key), func(b bool) {}, i.Window)
-		d.Show()
-	})
-	button.Importance = widget.DangerImportance
+	if !i.nodeConfig.isKeyStoreMem {
+		infoContent.Add(i.backupButton())
+	}
 
-	return button
+	return widget.NewCard("Info", "", infoContent)
 }
 
 func (i *index) addressContent() *fyne.Container {
@@ -84,16 +56,20 @@ func (i *index) pubkeyContent() *fyne.Container {
 func (i *index) stampsContent(batchRadio *widget.RadioGroup) *fyne.Container {
 	stampsHeader := container.NewHBox(widget.NewLabel("Postage stamps:"))
 	stamps := i.bl.GetUsableBatches()
+	details := widget.NewAccordion()
 
 	if len(stamps) != 0 {
-		selectedStamp := i.getPreferenceString(selectedStampPrefKey)
+		selectedBatch := i.getPreferenceString(batchPrefKey)
 		for _, v := range stamps {
-			batchRadio.Append(shortenHashOrAddress(hex.EncodeToString(v.ID())))
+			batchRadio.Append(batchOptionText(v))
+			details.Append(widget.NewAccordionItem(batchOptionText(v), i.batchDetails(v)))
+			if hex.EncodeToString(v.ID()) == selectedBatch {
+				batchRadio.SetSelected(batchOptionText(v))
+			}
 		}
-
-		batchRadio.SetSelected(selectedStamp)
 	}
-	return container.NewVBox(stampsHeader, batchRadio)
+	policyForm := widget.NewForm(widget.NewFormItem("Batch policy", i.getBatchPolicySelect()))
+	return container.NewVBox(stampsHeader, batchRadio, policyForm, details)
 }
 
 func (i *index) batchRadio() *widget.RadioGroup {
@@ -105,10 +81,9 @@ func (i *index) batchRadio() *widget.RadioGroup {
 		}
 		batches := i.bl.GetUsableBatches()
 		for _, v := range batches {
-			stamp := hex.EncodeToString(v.ID())
-			if s[0:6] == stamp[0:6] {
+			if batchOptionText(v) == s {
 				i.setPreference(selectedStampPrefKey, s)
-				i.setPreference(batchPrefKey, stamp)
+				i.setPreference(batchPrefKey, hex.EncodeToString(v.ID()))
 			}
 		}
 	})
@@ -117,12 +92,56 @@ func (i *index) batchRadio() *widget.RadioGroup {
 func (i *index) buyBatchButton(batchRadio *widget.RadioGroup) *widget.Button {
 	return widget.NewButton("Buy a postage batch", func() {
 		child := i.app.NewWindow("Buying a postage batch")
-		depthStr := defaultDepth
-		amountStr := defaultAmount
+		capacityStr := defaultCapacity
+		durationStr := defaultDuration
 		isImmutable := defaultImmutable
 		label := ""
+		var price, balance *big.Int
+		estimateLabel := widget.NewLabel("Loading storage price...")
+
+		estimate := func() (batchEstimate, error) {
+			capacity, duration, err := parseBatchTarget(capacityStr, durationStr)
+			if err != nil {
+				estimateLabel.SetText(err.Error())
+				return batchEstimate{}, err
+			}
+			est, err := estimateBatch(capacity, duration, price, i.networkProfile().BlockTime)
+			if err != nil {
+				estimateLabel.SetText(err.Error())
+				return batchEstimate{}, err
+			}
+			text := fmt.Sprintf("Depth %d, amount %s per chunk\nCapacity %s, cost %s %s",
+				est.depth, est.amount.String(), formatBytes(batchCapacity(est.depth)), formatBZZ(est.cost), SwarmTokenSymbol)
+			if balance != nil {
+				text += fmt.Sprintf("\nWallet balance %s %s", formatBZZ(balance), SwarmTokenSymbol)
+				if balance.Cmp(est.cost) < 0 {
+					err = fmt.Errorf("insufficient %s balance", SwarmTokenSymbol)
+					text += "\n" + err.Error()
+				}
+			}
+			estimateLabel.SetText(text)
+			return est, err
+		}
+		go func() {
+			c, err := i.stampsContract()
+			var p, b *big.Int
+			if err == nil {
+				p, err = c.LastPrice(context.Background())
+			}
+			if err == nil {
+				b, err = c.Balance(context.Background())
+			}
+			if err != nil {
+				i.logger.Log(fmt.Sprintf("failed to load storage price: %s", err.Error()))
+			}
+			fyne.Do(func() {
+				price, balance = p, b
+				estimate()
+			})
+		}()
+
 		content := container.NewStack()
-		buyBatchContent := i.buyBatchForm(&depthStr, &amountStr, &label, &isImmutable)
+		buyBatchContent := i.buyBatchForm(&capacityStr, &durationStr, &label, &isImmutable, func() { estimate() })
 		size := child.Canvas().Content().MinSize()
 		if size.Width < 250 {
 			size.Width = 250
@@ -133,19 +152,14 @@ func (i *index) buyBatchButton(batchRadio *widget.RadioGroup) *widget.Button {
 		child.Resize(size)
 
 		buyButton := widget.NewButton("Buy", func() {
-			amount, ok := big.NewInt(0).SetString(amountStr, 10)
-			if !ok {
-				i.showError(fmt.Errorf("invalid amountStr"))
-				return
-			}
-			depth, err := strconv.ParseUint(depthStr, 10, 8)
+			est, err := estimate()
 			if err != nil {
-				i.showError(fmt.Errorf("invalid depthStr %s", err.Error()))
+				i.showError(err)
 				return
 			}
 			child.Close()
-			i.showProgressWithMessage(fmt.Sprintf("Buying a postage batch\ndepth: %s, amount: %s, label: \"%s\", immutable: %t", depthStr, amountStr, label, isImmutable))
-			hash, id, err := i.bl.BuyStamp(amount, depth, label, isImmutable)
+			i.showProgressWithMessage(fmt.Sprintf("Buying a postage batch\ndepth: %d, amount: %s, label: \"%s\", immutable: %t", est.depth, est.amount.String(), label, isImmutable))
+			hash, id, err := i.bl.BuyStamp(est.amount, uint64(est.depth), label, isImmutable)
 			if err != nil {
 				i.hideProgress()
 				i.showError(err)
@@ -153,34 +167,50 @@ func (i *index) buyBatchButton(batchRadio *widget.RadioGroup) *widget.Button {
 			}
 			i.logger.Log(fmt.Sprintf("Batch created: %s", hash.String()))
 			i.hideProgress()
-			batchRadio.Append(shortenHashOrAddress(hex.EncodeToString(id)))
+			batchRadio.Append(batchText(label, id))
 		})
 		buyButton.Importance = widget.HighImportance
-		content.Objects = []fyne.CanvasObject{container.NewBorder(buyBatchContent, container.NewVBox(buyButton), nil, nil)}
+		content.Objects = []fyne.CanvasObject{container.NewBorder(container.NewVBox(buyBatchContent, estimateLabel), container.NewVBox(buyButton), nil, nil)}
 		child.SetContent(content)
 		child.Show()
 	})
 }
 
-func (i *index) buyBatchForm(depthStr, amountStr, label *string, isImmutable *bool) fyne.CanvasObject {
-	depthBind := binding.BindString(depthStr)
-	amountBind := binding.BindString(amountStr)
+// parseBatchTarget reads the capacity in GiB and the duration in days of the
+// buy batch form.
+func parseBatchTarget(capacityStr, durationStr string) (uint64, time.Duration, error) {
+	capacity, err := strconv.ParseFloat(capacityStr, 64)
+	if err != nil || capacity <= 0 {
+		return 0, 0, fmt.Errorf("invalid capacity %q", capacityStr)
+	}
+	days, err := strconv.ParseFloat(durationStr, 64)
+	if err != nil || days <= 0 {
+		return 0, 0, fmt.Errorf("invalid duration %q", durationStr)
+	}
+	return uint64(capacity * (1 << 30)), time.Duration(days * float64(24*time.Hour)), nil
+}
+
+func (i *index) buyBatchForm(capacityStr, durationStr, label *string, isImmutable *bool, onChanged func()) fyne.CanvasObject {
+	capacityBind := binding.BindString(capacityStr)
+	durationBind := binding.BindString(durationStr)
 	labelBind := binding.BindString(label)
 	immutableBind := binding.BindBool(isImmutable)
 
-	amountEntry := widget.NewEntryWithData(amountBind)
-	amountEntry.OnChanged = func(s string) {
-		err := amountBind.Set(s)
+	capacityEntry := widget.NewEntryWithData(capacityBind)
+	capacityEntry.OnChanged = func(s string) {
+		err := capacityBind.Set(s)
 		if err != nil {
-			i.logger.Log(fmt.Sprintf("failed to bind amount: %s", err.Error()))
+			i.logger.Log(fmt.Sprintf("failed to bind capacity: %s", err.Error()))
 		}
+		onChanged()
 	}
-	depthEntry := widget.NewEntryWithData(depthBind)
-	depthEntry.OnChanged = func(s string) {
-		err := depthBind.Set(s)
+	durationEntry := widget.NewEntryWithData(durationBind)
+	durationEntry.OnChanged = func(s string) {
+		err := durationBind.Set(s)
 		if err != nil {
-			i.logger.Log(fmt.Sprintf("failed to bind depth: %s", err.Error()))
+			i.logger.Log(fmt.Sprintf("failed to bind duration: %s", err.Error()))
 		}
+		onChanged()
 	}
 	labelEntry := widget.NewEntryWithData(labelBind)
 	labelEntry.OnChanged = func(s string) {
@@ -200,12 +230,12 @@ func (i *index) buyBatchForm(depthStr, amountStr, label *string, isImmutable *bo
 
 	optionsForm := widget.NewForm()
 	optionsForm.Append(
-		"Depth",
-		container.NewStack(depthEntry),
+		"Capacity (GiB)",
+		container.NewStack(capacityEntry),
 	)
 	optionsForm.Append(
-		"Amount",
-		container.NewStack(amountEntry),
+		"Duration (days)",
+		container.NewStack(durationEntry),
 	)
 	optionsForm.Append(
 		"Label",
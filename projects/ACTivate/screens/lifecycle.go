@@ -0,0 +1,49 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+
+	"activate/core"
+)
+
+// setupLifecycle shuts the node down once, whichever comes first: the window
+// closing, or the app stopping on quit, OS signal or a mobile OS ending it.
+func (i *index) setupLifecycle() {
+	i.Window.SetOnClosed(func() { i.shutdown("window closed") })
+	i.app.Lifecycle().SetOnStopped(func() { i.shutdown("app stopped") })
+}
+
+// shutdown cancels the goroutines bound to the window, closes the RPC
+// clients and stops the node within core.NodeShutdownTimeout. Failures are only
+// logged, the app is exiting anyway.
+func (i *index) shutdown(reason string) {
+	i.shutdownOnce.Do(func() {
+		i.stopUI(reason)
+		i.stopNode()
+		i.lockVault()
+	})
+}
+
+// stopUI cancels the goroutines bound to the window and detaches the node
+// from the UI. It must run on the UI thread.
+func (i *index) stopUI(reason string) {
+	i.logger.Log(fmt.Sprintf("Shutting down: %s", reason))
+	i.cancel()
+	i.stopAutoLockTimer()
+	i.detachNode()
+}
+
+// stopNode stops the node and logs the outcome, it can run off the UI thread.
+func (i *index) stopNode() {
+	err := i.svc.Stop()
+	switch {
+	case errors.Is(err, core.ErrRestartRequired):
+		// bee-lite closes its stores when the process exits
+		i.logger.Log("bee-lite cannot be stopped in-process, the node stops with the app")
+	case err != nil:
+		i.logger.Log(fmt.Sprintf("shutdown failed: %s", err.Error()))
+	default:
+		i.logger.Log("Node stopped")
+	}
+}
@@ -0,0 +1,75 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+
+	"activate/core"
+	"activate/localapi"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const localAPIPrefKey = core.LocalAPIPrefKey
+
+// startLocalAPI serves the local API if it is enabled in the preferences. It
+// stops with the window or stopLocalAPI.
+func (i *index) startLocalAPI() {
+	addr := i.getPreferenceString(localAPIPrefKey)
+	if addr == "" || i.stopAPI != nil || i.nodeConfig.isKeyStoreMem {
+		return
+	}
+	token, err := localapi.LoadToken(i.nodeConfig.path)
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("Local API disabled: %s", err.Error()))
+		return
+	}
+	ctx, cancel := context.WithCancel(i.ctx)
+	i.stopAPI = cancel
+	go func() {
+		if err := localapi.New(i.svc, token).ListenAndServe(ctx, addr); err != nil {
+			i.logger.Log(fmt.Sprintf("Local API stopped: %s", err.Error()))
+			fyne.Do(func() {
+				i.stopLocalAPI()
+				i.showError(fmt.Errorf("local API: %w", err))
+			})
+		}
+	}()
+}
+
+func (i *index) stopLocalAPI() {
+	if i.stopAPI != nil {
+		i.stopAPI()
+		i.stopAPI = nil
+	}
+}
+
+func (i *index) showLocalAPICard() *widget.Card {
+	tokenButton := widget.NewButton("Copy token", func() {
+		token, err := localapi.LoadToken(i.nodeConfig.path)
+		if err != nil {
+			i.showError(err)
+			return
+		}
+		i.Window.Clipboard().SetContent(token)
+	})
+	addr := i.getPreferenceString(localAPIPrefKey)
+	enabled := addr != ""
+	if addr == "" {
+		addr = localapi.DefaultAddr
+	}
+	apiCheck := widget.NewCheck(fmt.Sprintf("Serve on %s", addr), nil)
+	apiCheck.SetChecked(enabled)
+	apiCheck.OnChanged = func(enabled bool) {
+		if !enabled {
+			i.setPreference(localAPIPrefKey, "")
+			i.stopLocalAPI()
+			return
+		}
+		i.setPreference(localAPIPrefKey, addr)
+		i.startLocalAPI()
+	}
+	return widget.NewCard("Local API", "HTTP access for scripts on this computer", container.NewVBox(apiCheck, tokenButton))
+}
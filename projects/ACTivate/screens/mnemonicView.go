@@ -0,0 +1,135 @@
+package screens
+
+import (
+	"fmt"
+
+	"activate/identity"
+	"activate/secrets"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	createMnemonicOption  = "Create a new recovery phrase"
+	restoreMnemonicOption = "Restore from a recovery phrase"
+)
+
+// showMnemonicView creates the node identity from a new or an existing BIP-39
+// recovery phrase, before bee-lite generates a random key.
+func (i *index) showMnemonicView() fyne.CanvasObject {
+	i.intro.SetText("Create or restore your node identity with a recovery phrase")
+	content := container.NewStack()
+
+	newMnemonic, err := identity.NewMnemonic(identity.DefaultWords)
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("failed to generate recovery phrase: %s", err.Error()))
+	}
+	phraseLabel := widget.NewLabel(newMnemonic)
+	phraseLabel.Wrapping = fyne.TextWrapWord
+	phraseLabel.TextStyle.Monospace = true
+	writtenCheck := widget.NewCheck("I have written down the recovery phrase", nil)
+	createBox := container.NewVBox(
+		widget.NewLabel("Write these words down and keep them offline:"),
+		phraseLabel,
+		writtenCheck,
+	)
+
+	phraseEntry := widget.NewMultiLineEntry()
+	phraseEntry.SetPlaceHolder("Recovery phrase words")
+	phraseEntry.Wrapping = fyne.TextWrapWord
+	restoreBox := container.NewVBox(phraseEntry)
+	restoreBox.Hide()
+
+	modeRadio := widget.NewRadioGroup([]string{createMnemonicOption, restoreMnemonicOption}, func(s string) {
+		if s == restoreMnemonicOption {
+			createBox.Hide()
+			restoreBox.Show()
+		} else {
+			restoreBox.Hide()
+			createBox.Show()
+		}
+	})
+	modeRadio.SetSelected(createMnemonicOption)
+
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Passphrase for this device")
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Confirm passphrase")
+
+	nextButton := widget.NewButton("Next", func() {
+		mnemonic := newMnemonic
+		if modeRadio.Selected == restoreMnemonicOption {
+			mnemonic = identity.NormalizeMnemonic(phraseEntry.Text)
+		} else if !writtenCheck.Checked {
+			i.showError(fmt.Errorf("write down the recovery phrase first"))
+			return
+		}
+		if err := identity.ValidateMnemonic(mnemonic); err != nil {
+			i.showError(err)
+			return
+		}
+		if err := secrets.ValidatePassphrase(passwordEntry.Text); err != nil {
+			i.showError(err)
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			i.showError(fmt.Errorf("passphrases do not match"))
+			return
+		}
+
+		i.showProgressWithMessage("Creating node identity")
+		address, err := i.createIdentityFromMnemonic(mnemonic, passwordEntry.Text)
+		i.hideProgress()
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to create identity from recovery phrase: %s", err.Error()))
+			i.showError(err)
+			return
+		}
+
+		i.logger.Log(fmt.Sprintf("Node identity created from recovery phrase: %s", address))
+		phraseEntry.SetText("")
+		content.Objects = []fyne.CanvasObject{i.showWelcomeMessageView()}
+		content.Refresh()
+	})
+
+	backButton := widget.NewButton("Back", func() {
+		content.Objects = []fyne.CanvasObject{i.showPasswordView()}
+		content.Refresh()
+	})
+	backButton.Importance = widget.WarningImportance
+	nextButton.Importance = widget.HighImportance
+	content.Objects = []fyne.CanvasObject{container.NewBorder(
+		container.NewVBox(modeRadio, createBox, restoreBox, passwordEntry, confirmEntry),
+		container.NewVBox(nextButton, backButton), nil, nil)}
+	i.content = content
+	i.view = container.NewBorder(container.NewVBox(i.intro), nil, nil, nil, content)
+	i.view.Refresh()
+
+	return content
+}
+
+// createIdentityFromMnemonic sets up the vault and writes the key derived
+// from the mnemonic as the swarm key, encrypted with the node password.
+func (i *index) createIdentityFromMnemonic(mnemonic, passphrase string) (string, error) {
+	if i.keystoreExists() || i.hasVault() {
+		return "", identity.ErrKeyExists
+	}
+	key, err := identity.DeriveKey(mnemonic, identity.DefaultDerivationPath)
+	if err != nil {
+		return "", err
+	}
+	if err := i.createVault(passphrase, ""); err != nil {
+		return "", err
+	}
+	password, err := i.nodePassword()
+	if err == nil {
+		err = identity.WriteSwarmKey(i.nodeConfig.path, password, key)
+	}
+	if err != nil {
+		i.discardVault()
+		return "", err
+	}
+	return identity.Address(key)
+}
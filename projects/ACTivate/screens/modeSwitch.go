@@ -0,0 +1,156 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ethersphere/bee/v2/pkg/api"
+)
+
+// detachNode stops everything in the UI that depends on the running node. It
+// must run on the UI thread.
+func (i *index) detachNode() {
+	if i.stopStatus != nil {
+		i.stopStatus()
+		i.stopStatus = nil
+	}
+	if i.eventLogSubscription != nil {
+		i.eventLogSubscription.Unsubscribe()
+		i.eventLogSubscription = nil
+	}
+	i.stampsMu.Lock()
+	i.stamps = nil
+	i.stampsMu.Unlock()
+	i.bl = nil
+}
+
+// shutdownNode stops everything that depends on the running node and then the
+// node itself, see core.Service.Stop. It must run on the UI thread.
+func (i *index) shutdownNode() error {
+	i.detachNode()
+	return i.svc.Stop()
+}
+
+// restartNode starts the node with the given mode, the other options are
+// taken from cfg. It runs off the UI thread and updates the UI state through
+// fyne.Do once the node is up.
+func (i *index) restartNode(cfg nodeConfig, password, rpcEndpoint string, swapEnable bool) error {
+	if err := i.svc.Start(nodeOptions(&cfg, cfg.path, cfg.welcomeMessage, cfg.natAddress, rpcEndpoint, swapEnable), password); err != nil {
+		if stopErr := i.svc.Stop(); stopErr != nil {
+			i.logger.Log(stopErr.Error())
+		}
+		return err
+	}
+	contractErr := i.svc.ConnectContract(context.Background())
+	fyne.DoAndWait(func() {
+		i.bl = i.svc.Node()
+		i.nodeConfig.swapEnable = swapEnable
+		i.nodeConfig.rpcEndpoint = rpcEndpoint
+		i.contractConnected(contractErr)
+	})
+	return nil
+}
+
+// switchNodeMode restarts the running node in light or ultra-light mode. If
+// the new mode fails to start, the previous mode is started again. It runs
+// off the UI thread with a copy of the node config taken on it.
+func (i *index) switchNodeMode(cfg nodeConfig, swapEnable bool) error {
+	node := i.svc.Node()
+	if node == nil {
+		return core.ErrNodeNotStarted
+	}
+	if core.CheckNodeMode(node.BeeNodeMode(), swapEnable) == nil {
+		return nil
+	}
+	password, err := i.nodePassword()
+	if err != nil {
+		return err
+	}
+
+	rpcEndpoint := ""
+	if swapEnable {
+		profile := cfg.networkProfile()
+		rpcEndpoint = i.getPreferenceString(rpcEndpointPrefKey)
+		if rpcEndpoint == "" {
+			rpcEndpoint = profile.RPCEndpoint
+		}
+		if err := i.verifyRPCConnection(rpcEndpoint, profile); err != nil {
+			return err
+		}
+		if err := i.requireFundedWallet(); err != nil {
+			return err
+		}
+	}
+	if !i.svc.CanStop() {
+		i.setPreference(swapEnablePrefKey, swapEnable)
+		i.setPreference(rpcEndpointPrefKey, rpcEndpoint)
+		return core.ErrRestartRequired
+	}
+
+	fyne.DoAndWait(i.detachNode)
+	if err := i.svc.Stop(); err != nil {
+		fyne.Do(func() { i.bl = i.svc.Node() })
+		return err
+	}
+	if err := i.restartNode(cfg, password, rpcEndpoint, swapEnable); err != nil {
+		i.logger.Log(fmt.Sprintf("failed to switch node mode: %s, rolling back", err.Error()))
+		if rbErr := i.restartNode(cfg, password, cfg.rpcEndpoint, !swapEnable); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback failed: %w", rbErr))
+		}
+		return fmt.Errorf("switch failed, still running the previous mode: %w", err)
+	}
+
+	i.setPreference(swapEnablePrefKey, swapEnable)
+	i.setPreference(rpcEndpointPrefKey, rpcEndpoint)
+	return nil
+}
+
+func (i *index) modeSwitchButton() *widget.Button {
+	target, swapEnable := api.LightMode, true
+	if i.bl.BeeNodeMode() == api.LightMode {
+		target, swapEnable = api.UltraLightMode, false
+	}
+	button := widget.NewButton(fmt.Sprintf("Switch to %s mode", target), func() {
+		dialog.NewConfirm("Node mode",
+			fmt.Sprintf("The node restarts in %s mode.", target),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				i.showProgressWithMessage(fmt.Sprintf("Switching to %s mode", target))
+				cfg := *i.nodeConfig
+				go func() {
+					err := i.switchNodeMode(cfg, swapEnable)
+					fyne.Do(func() {
+						i.hideProgress()
+						if i.bl != nil {
+							i.loadMenuView()
+							i.setupDataContractSubscription()
+						} else {
+							// the node is gone, start over from the start view
+							menu := i.content
+							i.intro.Show()
+							menu.Objects = []fyne.CanvasObject{i.showStartView(false)}
+							menu.Refresh()
+						}
+						if err != nil {
+							i.logger.Log(err.Error())
+							i.showError(err)
+							return
+						}
+						i.logger.Log(fmt.Sprintf("Node running in %s mode", i.bl.BeeNodeMode()))
+					})
+				}()
+			}, i.Window).Show()
+	})
+	if swapEnable && i.networkProfile().UltraLightOnly {
+		button.Disable()
+	}
+	return button
+}
@@ -0,0 +1,66 @@
+package screens
+
+import (
+	"fmt"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+const networkPrefKey = core.NetworkPrefKey
+
+func networkDisplayNames() []string {
+	names := make([]string, 0, len(core.NetworkProfiles))
+	for _, p := range core.NetworkProfiles {
+		names = append(names, p.DisplayName)
+	}
+	return names
+}
+
+func findNetworkProfileByDisplayName(displayName string) *core.NetworkProfile {
+	for _, p := range core.NetworkProfiles {
+		if p.DisplayName == displayName {
+			return p
+		}
+	}
+	return nil
+}
+
+// logDeployments logs the data contracts loaded by the core service.
+func (i *index) logDeployments() {
+	for _, p := range core.NetworkProfiles {
+		if p.Deployment != nil {
+			i.logger.Log(fmt.Sprintf("%s data contract: %s from block %d (%s)", p.DisplayName, p.Deployment.ContractAddress, p.Deployment.DeploymentBlock, p.Deployment.Source))
+		}
+	}
+}
+
+func (i *index) networkProfile() *core.NetworkProfile {
+	return i.nodeConfig.networkProfile()
+}
+
+// networkProfile returns the profile of the selected network, the default
+// network if none is selected.
+func (c *nodeConfig) networkProfile() *core.NetworkProfile {
+	if p := core.FindNetworkProfile(c.network); p != nil {
+		return p
+	}
+	return core.FindNetworkProfile(core.DefaultNetwork)
+}
+
+func (i *index) getNetworkSelect() *widget.Select {
+	networkSelect := widget.NewSelect(networkDisplayNames(), func(s string) {
+		p := findNetworkProfileByDisplayName(s)
+		if p == nil {
+			return
+		}
+		if i.nodeConfig.network != p.Name && i.nodeConfig.swapEnable {
+			i.nodeConfig.rpcEndpoint = p.RPCEndpoint
+		}
+		i.nodeConfig.network = p.Name
+		i.logger.Log(fmt.Sprintf("Network selected: %s (chain ID %d)", p.DisplayName, p.ChainID))
+	})
+	networkSelect.SetSelected(i.networkProfile().DisplayName)
+	return networkSelect
+}
@@ -0,0 +1,141 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const outboxPrefKey = core.OutboxPrefKey
+
+// recheckOutbox waits in the background for the receipts of the shares still
+// pending when the app last stopped.
+func (i *index) recheckOutbox() {
+	go func() {
+		if err := i.svc.RecheckOutbox(i.ctx); err != nil {
+			i.logger.Log(fmt.Sprintf("Outbox check failed: %s", err.Error()))
+		}
+	}()
+}
+
+// outboxUpdated notifies the outcome of a sent share once it is mined.
+func (i *index) outboxUpdated(e core.OutboxEntry) {
+	if e.Status == core.OutboxPending {
+		return
+	}
+	title := fmt.Sprintf("Share %s", e.Status)
+	text := fmt.Sprintf("Transaction %s to %s", shortenHashOrAddress(e.TxHash), shortenHashOrAddress(e.Recipients()[0]))
+	if e.Error != "" {
+		text += ": " + e.Error
+	}
+	i.logger.Log(fmt.Sprintf("%s: %s", title, text))
+	fyne.Do(func() {
+		i.app.SendNotification(fyne.NewNotification(title, text))
+	})
+}
+
+func (i *index) outboxButton(minSize fyne.Size) *widget.Button {
+	return widget.NewButton("Sent", func() {
+		sentContent := container.NewVBox()
+		entries, err := i.svc.Outbox()
+		if err != nil {
+			i.showError(err)
+		}
+		// newest first
+		for n := len(entries) - 1; n >= 0; n-- {
+			sentContent.Add(i.outboxItem(entries[n]))
+		}
+		if len(entries) == 0 {
+			sentContent.Add(widget.NewLabel("Nothing sent yet"))
+		}
+
+		child := i.app.NewWindow("Sent")
+		size := child.Canvas().Content().Size()
+		if size.Width < minSize.Width {
+			size.Width = minSize.Width
+		}
+		if size.Height < minSize.Height {
+			size.Height = minSize.Height
+		}
+		child.Resize(size)
+		child.SetContent(container.NewScroll(sentContent))
+		child.Show()
+	})
+}
+
+func (i *index) outboxItem(e core.OutboxEntry) fyne.CanvasObject {
+	to := e.Recipients()
+	text := fmt.Sprintf("%s  %s\nTo: %s", e.Timestamp.Format("2006-01-02 15:04"), e.Status, shortenHashOrAddress(to[0]))
+	if len(to) > 1 {
+		text += fmt.Sprintf(" and %d more", len(to)-1)
+	}
+	if e.Reference != "" {
+		text += fmt.Sprintf("\nReference: %s", shortenHashOrAddress(e.Reference))
+	}
+	text += fmt.Sprintf("\nTx: %s", shortenHashOrAddress(e.TxHash))
+	if e.BlockNumber != 0 {
+		text += fmt.Sprintf("\nBlock %d, gas used %d", e.BlockNumber, e.GasUsed)
+	}
+	if e.Error != "" {
+		text += fmt.Sprintf("\nError: %s", e.Error)
+	}
+	if n := len(e.Cancels); n != 0 {
+		text += fmt.Sprintf("\nCancelled %d times", n)
+	}
+	if e.ResentAs != "" {
+		text += fmt.Sprintf("\nSent again as: %s", shortenHashOrAddress(e.ResentAs))
+	}
+	if e.MinedTx != "" {
+		text += fmt.Sprintf("\nMined as: %s", shortenHashOrAddress(e.MinedTx))
+	}
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+	var actions fyne.CanvasObject
+	if e.Status == core.OutboxPending {
+		txHash := common.HexToHash(e.TxHash)
+		speedUpButton := widget.NewButton("Speed up", func() {
+			i.replaceTransaction("Speed up", func(ctx context.Context) (common.Hash, error) {
+				return i.svc.SpeedUp(ctx, txHash)
+			})
+		})
+		cancelButton := widget.NewButton("Cancel", func() {
+			dialog.ShowConfirm("Cancel share", "Replace the transaction by an empty one, so that the share is not sent?", func(ok bool) {
+				if ok {
+					i.replaceTransaction("Cancel", func(ctx context.Context) (common.Hash, error) {
+						return i.svc.Cancel(ctx, txHash)
+					})
+				}
+			}, i.Window)
+		})
+		if len(e.Cancels) != 0 {
+			speedUpButton.Disable()
+		}
+		actions = container.NewHBox(speedUpButton, cancelButton)
+	}
+	return container.NewBorder(label, actions, nil, i.copyButton(e.TxHash))
+}
+
+// replaceTransaction sends the transaction made by replace, a cancellation or
+// the share sent again, and reports its hash. The outcome is notified like
+// the one of the first transaction.
+func (i *index) replaceTransaction(action string, replace func(ctx context.Context) (common.Hash, error)) {
+	i.showProgressWithMessage("Sending the transaction...")
+	go func() {
+		txHash, err := replace(i.ctx)
+		fyne.Do(func() {
+			i.hideProgress()
+			if err != nil {
+				i.showError(fmt.Errorf("%s: %w", action, err))
+				return
+			}
+			dialog.ShowInformation(action, fmt.Sprintf("Replacement sent: %s", txHash.Hex()), i.Window)
+		})
+	}()
+}
@@ -0,0 +1,36 @@
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"activate/core"
+)
+
+const (
+	eventsRPCPrefKey   = core.EventsRPCPrefKey
+	rpcFallbackPrefKey = core.RPCFallbackPrefKey
+)
+
+// failoverContractRPC switches the contract layer to the next healthy
+// endpoint and resubscribes to the contract events.
+func (i *index) failoverContractRPC() {
+	err := i.svc.FailoverRPC(context.Background())
+	switch {
+	case errors.Is(err, core.ErrNoDataContract):
+		return
+	case err != nil:
+		i.logger.Log(fmt.Sprintf("Contract RPC failover failed: %v", err))
+		i.setEventMessage("No healthy RPC endpoint left. Check logs.")
+		return
+	}
+	i.setupDataContractSubscription()
+}
+
+func (i *index) activeRPCText() string {
+	if i.svc.ActiveRPC() == "" {
+		return "Contract RPC: not connected"
+	}
+	return fmt.Sprintf("Contract RPC: %s", core.RedactRPCEndpoint(i.svc.ActiveRPC()))
+}
@@ -0,0 +1,161 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"activate/core"
+	"activate/secrets"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/keystore"
+	filekeystore "github.com/ethersphere/bee/v2/pkg/keystore/file"
+)
+
+const (
+	nodePasswordSecret = core.NodePasswordSecret
+	// legacyDefaultPassword was used for the keystore before the vault existed,
+	// unless the user typed a password during onboarding.
+	legacyDefaultPassword = "defaultpassword"
+	swarmKeyFile          = "/keys/swarm.key"
+	keysDir               = "keys"
+	swarmKeyName          = "swarm"
+)
+
+func (i *index) vaultPath() string {
+	return filepath.Join(i.nodeConfig.path, secrets.FileName)
+}
+
+func (i *index) hasVault() bool {
+	return !i.nodeConfig.isKeyStoreMem && secrets.Exists(i.vaultPath())
+}
+
+func (i *index) vaultUnlocked() bool {
+	return i.vault != nil && !i.vault.Locked()
+}
+
+func (i *index) keystoreExists() bool {
+	if i.nodeConfig.isKeyStoreMem {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(i.nodeConfig.path, swarmKeyFile))
+	return err == nil
+}
+
+// createVault sets up the vault with the node password. Existing installs keep
+// the password their keystore is encrypted with, new ones get a random one.
+// legacyPassword is the password typed by the user for an existing keystore,
+// it may be empty when it was never set.
+func (i *index) createVault(passphrase, legacyPassword string) error {
+	password := ""
+	if i.keystoreExists() {
+		password = legacyPassword
+		if password == "" {
+			password = i.getPreferenceString(passwordPrefKey)
+		}
+		if password == "" {
+			password = legacyDefaultPassword
+		}
+		if err := i.verifyKeystorePassword(password); err != nil {
+			return err
+		}
+		i.logger.Log("Moving the existing node password into the vault")
+	} else {
+		var err error
+		password, err = core.NewNodePassword()
+		if err != nil {
+			return err
+		}
+	}
+
+	var vault *secrets.Vault
+	if i.nodeConfig.isKeyStoreMem {
+		vault = secrets.NewMemory()
+	} else {
+		var err error
+		vault, err = secrets.Create(i.vaultPath(), passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := vault.Set(nodePasswordSecret, []byte(password)); err != nil {
+		vault.Lock()
+		if !i.nodeConfig.isKeyStoreMem {
+			_ = os.Remove(i.vaultPath())
+		}
+		return fmt.Errorf("failed to store node password: %w", err)
+	}
+	i.removePreference(passwordPrefKey)
+	i.vault = vault
+	return nil
+}
+
+// unlockVault opens the vault and checks the node password it holds still
+// opens the keystore, so a mismatch shows up before bee-lite is started.
+func (i *index) unlockVault(passphrase string) error {
+	vault, err := secrets.Open(i.vaultPath(), passphrase)
+	if err != nil {
+		return err
+	}
+	password, err := vault.Get(nodePasswordSecret)
+	if err != nil {
+		vault.Lock()
+		return fmt.Errorf("node password: %w", err)
+	}
+	if err := i.verifyKeystorePassword(string(password)); err != nil {
+		vault.Lock()
+		return err
+	}
+	i.vault = vault
+	i.removePreference(passwordPrefKey)
+	return nil
+}
+
+// verifyKeystorePassword decrypts the swarm key with the password. It does
+// nothing if there is no keystore yet.
+func (i *index) verifyKeystorePassword(password string) error {
+	if !i.keystoreExists() {
+		return nil
+	}
+	ks := filekeystore.New(filepath.Join(i.nodeConfig.path, keysDir))
+	_, _, err := ks.Key(swarmKeyName, password, crypto.EDGSecp256_K1)
+	if errors.Is(err, keystore.ErrInvalidPassword) {
+		return errors.New("the node password does not open the keystore")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open the keystore: %w", err)
+	}
+	return nil
+}
+
+// discardVault removes a vault that was set up by an onboarding step that
+// failed afterwards.
+func (i *index) discardVault() {
+	i.lockVault()
+	i.vault = nil
+	if !i.nodeConfig.isKeyStoreMem {
+		if err := os.Remove(i.vaultPath()); err != nil && !os.IsNotExist(err) {
+			i.logger.Log(fmt.Sprintf("failed to remove vault: %s", err.Error()))
+		}
+	}
+}
+
+func (i *index) lockVault() {
+	if i.vault != nil {
+		i.vault.Lock()
+	}
+}
+
+func (i *index) nodePassword() (string, error) {
+	if i.vault == nil {
+		return "", secrets.ErrLocked
+	}
+	password, err := i.vault.Get(nodePasswordSecret)
+	if err != nil {
+		return "", fmt.Errorf("node password: %w", err)
+	}
+	return string(password), nil
+}
@@ -0,0 +1,509 @@
+package screens
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/config"
+	"github.com/ethersphere/bee/v2/pkg/postage"
+	"github.com/ethersphere/bee/v2/pkg/transaction"
+	"github.com/ethersphere/go-sw3-abi/sw3abi"
+)
+
+const (
+	// bzzDecimals is the number of decimals of xBZZ, one xBZZ is 1e16 PLUR.
+	bzzDecimals        = 16
+	batchNearFullRatio = 0.9
+	batchExpiryWarning = 24 * time.Hour
+	// minBatchDepth and maxBatchDepth bound the depth accepted by the
+	// postage contract, the minimum is one above the bucket depth.
+	minBatchDepth = 17
+	maxBatchDepth = 40
+	chunkSize     = 4096
+)
+
+var errNoPostageContract = errors.New("no postage contract on this network")
+
+// stampsContract calls the PostageStamp contract through the transaction
+// service of the node, for the batch operations bee-lite does not expose.
+type stampsContract struct {
+	txService    transaction.Service
+	owner        common.Address
+	address      common.Address
+	postageABI   abi.ABI
+	erc20ABI     abi.ABI
+	blockTime    time.Duration
+	bzzTokenAddr common.Address
+}
+
+func newStampsContract(txService transaction.Service, owner common.Address, chainID int64, blockTime time.Duration) (*stampsContract, error) {
+	if txService == nil {
+		return nil, fmt.Errorf("transaction service is not available")
+	}
+	cfg, ok := config.GetByChainID(chainID)
+	if !ok {
+		return nil, errNoPostageContract
+	}
+	postageABI, err := abi.JSON(strings.NewReader(cfg.PostageStampABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postage stamp ABI: %w", err)
+	}
+	erc20ABI, err := abi.JSON(strings.NewReader(sw3abi.ERC20ABIv0_6_5))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+	return &stampsContract{
+		txService:  txService,
+		owner:      owner,
+		address:    cfg.PostageStampAddress,
+		postageABI: postageABI,
+		erc20ABI:   erc20ABI,
+		blockTime:  blockTime,
+	}, nil
+}
+
+func (c *stampsContract) call(ctx context.Context, to common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	callData, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.txService.Call(ctx, &transaction.TxRequest{
+		To:   &to,
+		Data: callData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	values, err := contractABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s: empty result", method)
+	}
+	return values, nil
+}
+
+func (c *stampsContract) bzzToken(ctx context.Context) (common.Address, error) {
+	if c.bzzTokenAddr != (common.Address{}) {
+		return c.bzzTokenAddr, nil
+	}
+	values, err := c.call(ctx, c.address, c.postageABI, "bzzToken")
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr, ok := values[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("bzzToken: unexpected result %T", values[0])
+	}
+	c.bzzTokenAddr = addr
+	return addr, nil
+}
+
+// LastPrice returns the current storage price in PLUR per chunk per block.
+func (c *stampsContract) LastPrice(ctx context.Context) (*big.Int, error) {
+	values, err := c.call(ctx, c.address, c.postageABI, "lastPrice")
+	if err != nil {
+		return nil, err
+	}
+	return toBigInt(values[0])
+}
+
+// RemainingBalance returns the per chunk balance left on the batch.
+func (c *stampsContract) RemainingBalance(ctx context.Context, batchID []byte) (*big.Int, error) {
+	values, err := c.call(ctx, c.address, c.postageABI, "remainingBalance", common.BytesToHash(batchID))
+	if err != nil {
+		return nil, err
+	}
+	return toBigInt(values[0])
+}
+
+// TTL estimates how long the batch stays funded at the current price.
+func (c *stampsContract) TTL(ctx context.Context, batchID []byte) (time.Duration, error) {
+	remaining, err := c.RemainingBalance(ctx, batchID)
+	if err != nil {
+		return 0, err
+	}
+	price, err := c.LastPrice(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if price.Sign() == 0 {
+		return 0, fmt.Errorf("storage price is zero")
+	}
+	blocks := new(big.Int).Div(remaining, price)
+	if !blocks.IsInt64() {
+		return time.Duration(1<<63 - 1), nil
+	}
+	return time.Duration(blocks.Int64()) * c.blockTime, nil
+}
+
+// Balance returns the xBZZ balance of the node wallet in PLUR.
+func (c *stampsContract) Balance(ctx context.Context) (*big.Int, error) {
+	token, err := c.bzzToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := c.call(ctx, token, c.erc20ABI, "balanceOf", c.owner)
+	if err != nil {
+		return nil, err
+	}
+	return toBigInt(values[0])
+}
+
+// TopUp adds amount PLUR per chunk to the batch, approving the total cost for
+// all 2^depth chunks first.
+func (c *stampsContract) TopUp(ctx context.Context, batchID []byte, depth uint8, amount *big.Int) (common.Hash, error) {
+	total := batchCost(amount, depth)
+	balance, err := c.Balance(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if balance.Cmp(total) < 0 {
+		return common.Hash{}, fmt.Errorf("insufficient funds: top-up costs %s %s, wallet has %s", formatBZZ(total), SwarmTokenSymbol, formatBZZ(balance))
+	}
+
+	token, err := c.bzzToken(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	approveData, err := c.erc20ABI.Pack("approve", c.address, total)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if _, err := c.send(ctx, token, approveData, "Approve tokens for postage batch top up"); err != nil {
+		return common.Hash{}, fmt.Errorf("approve: %w", err)
+	}
+
+	topUpData, err := c.postageABI.Pack("topUp", common.BytesToHash(batchID), amount)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return c.send(ctx, c.address, topUpData, "Postage batch top up")
+}
+
+// Dilute increases the depth of the batch. The balance per chunk is halved
+// for every additional depth, so the TTL shrinks accordingly.
+func (c *stampsContract) Dilute(ctx context.Context, batchID []byte, newDepth uint8) (common.Hash, error) {
+	callData, err := c.postageABI.Pack("increaseDepth", common.BytesToHash(batchID), newDepth)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return c.send(ctx, c.address, callData, "Postage batch dilute")
+}
+
+func (c *stampsContract) send(ctx context.Context, to common.Address, data []byte, description string) (common.Hash, error) {
+	request := &transaction.TxRequest{
+		To:          &to,
+		Data:        data,
+		Value:       big.NewInt(0),
+		Description: description,
+	}
+	txHash, err := c.txService.Send(ctx, request, transaction.DefaultTipBoostPercent)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	receipt, err := c.txService.WaitForReceipt(ctx, txHash)
+	if err != nil {
+		return txHash, err
+	}
+	if receipt.Status == 0 {
+		return txHash, transaction.ErrTransactionReverted
+	}
+	return txHash, nil
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, nil
+	case uint64:
+		return new(big.Int).SetUint64(n), nil
+	case uint32:
+		return big.NewInt(int64(n)), nil
+	default:
+		return nil, fmt.Errorf("unexpected numeric type %T", v)
+	}
+}
+
+// batchCost is the PLUR needed to fund all chunks of a batch of the given
+// depth with amount per chunk.
+func batchCost(amount *big.Int, depth uint8) *big.Int {
+	return new(big.Int).Lsh(amount, uint(depth))
+}
+
+// batchEstimate is the depth and amount needed for a batch of a given
+// capacity and duration at the current price.
+type batchEstimate struct {
+	depth  uint8
+	amount *big.Int
+	cost   *big.Int
+}
+
+// estimateBatch picks the smallest depth whose theoretical capacity holds
+// capacity bytes, and the per chunk amount that pays for duration at price.
+func estimateBatch(capacity uint64, duration time.Duration, price *big.Int, blockTime time.Duration) (batchEstimate, error) {
+	if capacity == 0 {
+		return batchEstimate{}, fmt.Errorf("capacity must be greater than zero")
+	}
+	if duration < blockTime || blockTime <= 0 {
+		return batchEstimate{}, fmt.Errorf("duration must be at least %s", blockTime)
+	}
+	if price == nil || price.Sign() <= 0 {
+		return batchEstimate{}, fmt.Errorf("storage price is not available")
+	}
+	chunks := (capacity + chunkSize - 1) / chunkSize
+	depth := uint8(bits.Len64(chunks - 1))
+	if depth < minBatchDepth {
+		depth = minBatchDepth
+	}
+	if depth > maxBatchDepth {
+		return batchEstimate{}, fmt.Errorf("capacity is larger than the biggest batch of %s", formatBytes(batchCapacity(maxBatchDepth)))
+	}
+	blocks := int64((duration + blockTime - 1) / blockTime)
+	amount := new(big.Int).Mul(big.NewInt(blocks), price)
+	return batchEstimate{
+		depth:  depth,
+		amount: amount,
+		cost:   batchCost(amount, depth),
+	}, nil
+}
+
+// batchCapacity is the theoretical capacity of a batch in bytes. Immutable
+// batches stop accepting chunks once any bucket is full, so they fit less.
+func batchCapacity(depth uint8) uint64 {
+	return (uint64(1) << depth) * chunkSize
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatBZZ formats a PLUR amount as xBZZ with four decimals.
+func formatBZZ(plur *big.Int) string {
+	f := new(big.Float).SetInt(plur)
+	f.Quo(f, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(bzzDecimals), nil)))
+	return f.Text('f', 4)
+}
+
+func formatTTL(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "expired"
+	}
+	days := int(ttl.Hours()) / 24
+	hours := int(ttl.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(ttl.Minutes())%60)
+}
+
+// batchUsage returns how full the fullest bucket of the batch is, from 0 to 1.
+func batchUsage(stamp *postage.StampIssuer) float64 {
+	if stamp.Depth() <= stamp.BucketDepth() {
+		return 0
+	}
+	return float64(stamp.Utilization()) / float64(stamp.BucketUpperBound())
+}
+
+func batchOptionText(stamp *postage.StampIssuer) string {
+	return batchText(stamp.Label(), stamp.ID())
+}
+
+func batchText(label string, id []byte) string {
+	short := shortenHashOrAddress(hex.EncodeToString(id))
+	if label == "" {
+		return short
+	}
+	return fmt.Sprintf("%s (%s)", label, short)
+}
+
+// stampsContract returns the postage contract client, created on first use
+// because the transaction service only exists once the node is running.
+func (i *index) stampsContract() (*stampsContract, error) {
+	i.stampsMu.Lock()
+	defer i.stampsMu.Unlock()
+	if i.stamps != nil {
+		return i.stamps, nil
+	}
+	if i.bl == nil {
+		return nil, fmt.Errorf("node is not running")
+	}
+	profile := i.networkProfile()
+	c, err := newStampsContract(i.bl.TransactionService(), i.bl.OverlayEthAddress(), profile.ChainID, profile.BlockTime)
+	if err != nil {
+		return nil, err
+	}
+	i.stamps = c
+	return c, nil
+}
+
+// batchTTL returns the estimated TTL of the batch, cached until the batch is
+// topped up or diluted.
+func (i *index) batchTTL(batchID []byte) (time.Duration, error) {
+	key := hex.EncodeToString(batchID)
+	if v, ok := i.batchTTLs.Load(key); ok {
+		return v.(time.Duration), nil
+	}
+	c, err := i.stampsContract()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	ttl, err := c.TTL(ctx, batchID)
+	if err != nil {
+		return 0, err
+	}
+	i.batchTTLs.Store(key, ttl)
+	return ttl, nil
+}
+
+// batchWarnings lists the reasons the batch may not take more data.
+func (i *index) batchWarnings(stamp *postage.StampIssuer) []string {
+	var warnings []string
+	if usage := batchUsage(stamp); usage >= batchNearFullRatio {
+		warnings = append(warnings, fmt.Sprintf("Batch %s is %.0f%% full.", batchOptionText(stamp), usage*100))
+	}
+	ttl, err := i.batchTTL(stamp.ID())
+	if err != nil {
+		i.logger.Log(fmt.Sprintf("failed to get batch TTL: %s", err.Error()))
+	} else if ttl < batchExpiryWarning {
+		warnings = append(warnings, fmt.Sprintf("Batch %s expires in %s.", batchOptionText(stamp), formatTTL(ttl)))
+	}
+	return warnings
+}
+
+func (i *index) batchDetails(stamp *postage.StampIssuer) fyne.CanvasObject {
+	ttlLabel := widget.NewLabel("loading...")
+	go func() {
+		text := "unknown"
+		ttl, err := i.batchTTL(stamp.ID())
+		if err != nil {
+			i.logger.Log(fmt.Sprintf("failed to get batch TTL: %s", err.Error()))
+		} else {
+			text = fmt.Sprintf("%s (until %s)", formatTTL(ttl), time.Now().Add(ttl).Format("2006-01-02 15:04"))
+		}
+		fyne.Do(func() { ttlLabel.SetText(text) })
+	}()
+
+	details := widget.NewForm(
+		widget.NewFormItem("ID", widget.NewLabel(shortenHashOrAddress(hex.EncodeToString(stamp.ID())))),
+		widget.NewFormItem("Depth", widget.NewLabel(fmt.Sprintf("%d (bucket depth %d)", stamp.Depth(), stamp.BucketDepth()))),
+		widget.NewFormItem("Amount", widget.NewLabel(stamp.Amount().String())),
+		widget.NewFormItem("Used", widget.NewLabel(fmt.Sprintf("%.1f%%", batchUsage(stamp)*100))),
+		widget.NewFormItem("Immutable", widget.NewLabel(strconv.FormatBool(stamp.ImmutableFlag()))),
+		widget.NewFormItem("TTL", ttlLabel),
+	)
+	return container.NewVBox(details, container.NewHBox(i.topUpButton(stamp), i.diluteButton(stamp)), i.groupDefaultButtons(stamp))
+}
+
+func (i *index) topUpButton(stamp *postage.StampIssuer) *widget.Button {
+	return widget.NewButton("Top up", func() {
+		amountEntry := widget.NewEntry()
+		amountEntry.SetPlaceHolder("PLUR per chunk")
+		costLabel := widget.NewLabel("")
+		amountEntry.OnChanged = func(s string) {
+			amount, ok := new(big.Int).SetString(s, 10)
+			if !ok || amount.Sign() <= 0 {
+				costLabel.SetText("")
+				return
+			}
+			costLabel.SetText(fmt.Sprintf("%s %s", formatBZZ(batchCost(amount, stamp.Depth())), SwarmTokenSymbol))
+		}
+		d := dialog.NewForm("Top up "+batchOptionText(stamp), "Top up", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Amount", amountEntry),
+				widget.NewFormItem("Cost", costLabel),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				amount, valid := new(big.Int).SetString(amountEntry.Text, 10)
+				if !valid || amount.Sign() <= 0 {
+					i.showError(fmt.Errorf("invalid amount"))
+					return
+				}
+				i.runBatchTx(fmt.Sprintf("Topping up batch %s", batchOptionText(stamp)), stamp, func(c *stampsContract) (common.Hash, error) {
+					return c.TopUp(context.Background(), stamp.ID(), stamp.Depth(), amount)
+				})
+			}, i.Window)
+		d.Show()
+	})
+}
+
+func (i *index) diluteButton(stamp *postage.StampIssuer) *widget.Button {
+	button := widget.NewButton("Dilute", func() {
+		depthEntry := widget.NewEntry()
+		depthEntry.SetText(strconv.Itoa(int(stamp.Depth()) + 1))
+		d := dialog.NewForm("Dilute "+batchOptionText(stamp), "Dilute", "Cancel",
+			[]*widget.FormItem{
+				{Text: "New depth", Widget: depthEntry, HintText: "Every extra depth doubles the capacity and halves the TTL"},
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				depth, err := strconv.ParseUint(depthEntry.Text, 10, 8)
+				if err != nil || uint8(depth) <= stamp.Depth() {
+					i.showError(fmt.Errorf("new depth must be greater than %d", stamp.Depth()))
+					return
+				}
+				i.runBatchTx(fmt.Sprintf("Diluting batch %s to depth %d", batchOptionText(stamp), depth), stamp, func(c *stampsContract) (common.Hash, error) {
+					return c.Dilute(context.Background(), stamp.ID(), uint8(depth))
+				})
+			}, i.Window)
+		d.Show()
+	})
+	if stamp.ImmutableFlag() {
+		button.Disable()
+	}
+	return button
+}
+
+func (i *index) runBatchTx(message string, stamp *postage.StampIssuer, tx func(c *stampsContract) (common.Hash, error)) {
+	c, err := i.stampsContract()
+	if err != nil {
+		i.showError(err)
+		return
+	}
+	i.showProgressWithMessage(message)
+	go func() {
+		hash, err := tx(c)
+		i.batchTTLs.Delete(hex.EncodeToString(stamp.ID()))
+		fyne.Do(func() {
+			i.hideProgress()
+			if err != nil {
+				i.logger.Log(fmt.Sprintf("%s failed: %s", message, err.Error()))
+				i.showError(err)
+				return
+			}
+			i.logger.Log(fmt.Sprintf("%s: %s", message, hash.String()))
+			dialog.NewInformation("Postage batch", "Transaction confirmed. The batch details update once the node syncs the change.", i.Window).Show()
+		})
+	}()
+}
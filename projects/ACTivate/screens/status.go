@@ -0,0 +1,225 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ethersphere/bee/v2/pkg/api"
+	"github.com/ethersphere/bee/v2/pkg/util/ioutil"
+)
+
+const (
+	statusRefreshInterval = 30 * time.Second
+	statusRPCTimeout      = 10 * time.Second
+	// slowRPCLatency marks the RPC as degraded.
+	slowRPCLatency = 2 * time.Second
+)
+
+// depthReporter and reserveReporter are implemented by node APIs that expose
+// the topology and the reserve. bee-lite does not yet, so the dashboard shows
+// them only when available.
+type depthReporter interface {
+	NeighborhoodDepth() uint8
+}
+
+type reserveReporter interface {
+	ReserveSize() int
+	ReserveCapacity() int
+}
+
+type health int
+
+const (
+	healthOK health = iota
+	healthDegraded
+	healthDown
+)
+
+func (h health) String() string {
+	switch h {
+	case healthOK:
+		return "Healthy"
+	case healthDegraded:
+		return "Degraded"
+	default:
+		return "Down"
+	}
+}
+
+func (h health) color() color.Color {
+	switch h {
+	case healthOK:
+		return color.NRGBA{R: 0x2e, G: 0xa0, B: 0x43, A: 0xff}
+	case healthDegraded:
+		return color.NRGBA{R: 0xe0, G: 0xa0, B: 0x00, A: 0xff}
+	default:
+		return color.NRGBA{R: 0xd0, G: 0x30, B: 0x30, A: 0xff}
+	}
+}
+
+// nodeStatus is a snapshot of the node and its RPC connection.
+type nodeStatus struct {
+	peers      int
+	mode       api.BeeNodeMode
+	depth      string
+	reserve    string
+	localStore string
+	syncState  string
+	rpcLatency time.Duration
+	rpcErr     error
+}
+
+func (s *nodeStatus) health() health {
+	if s.peers == 0 {
+		return healthDown
+	}
+	if s.mode == api.LightMode && s.rpcErr != nil {
+		return healthDown
+	}
+	if s.rpcErr != nil || s.rpcLatency > slowRPCLatency || s.syncState != "synced" {
+		return healthDegraded
+	}
+	return healthOK
+}
+
+func (i *index) collectStatus(ctx context.Context) *nodeStatus {
+	s := &nodeStatus{
+		peers:      i.bl.ConnectedPeerCount(),
+		mode:       i.bl.BeeNodeMode(),
+		depth:      "n/a",
+		reserve:    "n/a",
+		localStore: "n/a",
+		syncState:  "n/a",
+	}
+	var node interface{} = i.bl
+	if r, ok := node.(depthReporter); ok {
+		s.depth = fmt.Sprintf("%d", r.NeighborhoodDepth())
+	}
+	if r, ok := node.(reserveReporter); ok {
+		s.reserve = fmt.Sprintf("%d / %d chunks", r.ReserveSize(), r.ReserveCapacity())
+	}
+	if !i.nodeConfig.isKeyStoreMem && i.nodeConfig.path != "" {
+		if size, err := dirSize(filepath.Join(i.nodeConfig.path, ioutil.DataPathLocalstore)); err == nil {
+			s.localStore = fmt.Sprintf("%s on disk, cache %d chunks", formatBytes(size), core.CacheCapacity)
+		}
+	}
+
+	client := i.svc.EthClient()
+	if client == nil {
+		s.rpcErr = fmt.Errorf("not connected")
+		return s
+	}
+	ctx, cancel := context.WithTimeout(ctx, statusRPCTimeout)
+	defer cancel()
+	started := time.Now()
+	progress, err := client.SyncProgress(ctx)
+	s.rpcLatency = time.Since(started)
+	switch {
+	case err != nil:
+		s.rpcErr = err
+	case progress == nil:
+		s.syncState = "synced"
+	default:
+		s.syncState = fmt.Sprintf("syncing %d / %d", progress.CurrentBlock, progress.HighestBlock)
+	}
+	return s
+}
+
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
+
+// showStatusDashboard returns the health indicator for the menu header and
+// the dashboard card. Both refresh on a ticker that stops with the window or
+// when the menu is rebuilt.
+func (i *index) showStatusDashboard() (fyne.CanvasObject, *widget.Card) {
+	dot := canvas.NewCircle(healthDown.color())
+	dot.Resize(fyne.NewSize(12, 12))
+	healthLabel := widget.NewLabel("Checking...")
+	indicator := container.NewHBox(container.NewGridWrap(fyne.NewSize(12, 12), dot), healthLabel)
+
+	peersLabel := widget.NewLabel("")
+	modeLabel := widget.NewLabel("")
+	depthLabel := widget.NewLabel("")
+	reserveLabel := widget.NewLabel("")
+	localStoreLabel := widget.NewLabel("")
+	syncLabel := widget.NewLabel("")
+	rpcLabel := widget.NewLabel("")
+	form := widget.NewForm(
+		widget.NewFormItem("Peers", peersLabel),
+		widget.NewFormItem("Mode", modeLabel),
+		widget.NewFormItem("Depth", depthLabel),
+		widget.NewFormItem("Reserve", reserveLabel),
+		widget.NewFormItem("Local store", localStoreLabel),
+		widget.NewFormItem("Chain sync", syncLabel),
+		widget.NewFormItem("RPC", rpcLabel),
+	)
+	card := widget.NewCard("Status", "", form)
+
+	update := func(s *nodeStatus) {
+		h := s.health()
+		dot.FillColor = h.color()
+		dot.Refresh()
+		healthLabel.SetText(h.String())
+		peersLabel.SetText(fmt.Sprintf("%d", s.peers))
+		modeLabel.SetText(s.mode.String())
+		depthLabel.SetText(s.depth)
+		reserveLabel.SetText(s.reserve)
+		localStoreLabel.SetText(s.localStore)
+		syncLabel.SetText(s.syncState)
+		if s.rpcErr != nil {
+			rpcLabel.SetText(fmt.Sprintf("%s: %s", i.activeRPCText(), s.rpcErr.Error()))
+		} else {
+			rpcLabel.SetText(fmt.Sprintf("%s, %d ms", i.activeRPCText(), s.rpcLatency.Milliseconds()))
+		}
+		card.SetSubTitle(fmt.Sprintf("Updated %s", time.Now().Format("15:04:05")))
+	}
+
+	if i.stopStatus != nil {
+		i.stopStatus()
+	}
+	ctx, cancel := context.WithCancel(i.ctx)
+	i.stopStatus = cancel
+	go func() {
+		ticker := time.NewTicker(statusRefreshInterval)
+		defer ticker.Stop()
+		for {
+			s := i.collectStatus(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			fyne.Do(func() { update(s) })
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return indicator, card
+}
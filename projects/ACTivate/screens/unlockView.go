@@ -0,0 +1,135 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"activate/secrets"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	autoLockPrefKey        = "autoLockMinutes"
+	defaultAutoLockMinutes = 5
+)
+
+var autoLockOptions = []int{0, 1, 5, 15, 30}
+
+func autoLockText(minutes int) string {
+	switch minutes {
+	case 0:
+		return "Never"
+	case 1:
+		return "After 1 minute in background"
+	default:
+		return fmt.Sprintf("After %d minutes in background", minutes)
+	}
+}
+
+// showUnlockView asks for the vault passphrase and calls onUnlocked once the
+// vault is open and its node password is verified against the keystore.
+func (i *index) showUnlockView(onUnlocked func()) fyne.CanvasObject {
+	title := widget.NewLabel("Enter your passphrase to unlock your node")
+	title.Wrapping = fyne.TextWrapWord
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Passphrase")
+
+	unlock := func() {
+		if passphraseEntry.Text == "" {
+			i.showError(fmt.Errorf("passphrase cannot be blank"))
+			return
+		}
+
+		i.showProgressWithMessage("Unlocking")
+		if i.isDuressPassphrase(passphraseEntry.Text) {
+			i.hideProgress()
+			i.wipe()
+			return
+		}
+		err := i.unlockVault(passphraseEntry.Text)
+		i.hideProgress()
+		passphraseEntry.SetText("")
+		if err != nil {
+			if errors.Is(err, secrets.ErrWrongPassphrase) {
+				i.logger.Log("Unlock failed: wrong passphrase")
+			} else {
+				i.logger.Log(fmt.Sprintf("Unlock failed: %s", err.Error()))
+			}
+			i.showError(err)
+			return
+		}
+
+		i.logger.Log("Unlocked")
+		onUnlocked()
+	}
+	passphraseEntry.OnSubmitted = func(string) { unlock() }
+
+	unlockButton := widget.NewButton("Unlock", unlock)
+	unlockButton.Importance = widget.HighImportance
+	return container.NewBorder(container.NewVBox(title, passphraseEntry), unlockButton, nil, nil)
+}
+
+// lock wipes the unlocked secrets and covers the window with the unlock view.
+// The node keeps running, the previous content comes back after unlocking.
+func (i *index) lock() {
+	if !i.vaultUnlocked() {
+		return
+	}
+	i.stopAutoLockTimer()
+	i.lockVault()
+	i.logger.Log("Locked")
+
+	previous := i.Window.Content()
+	i.Window.SetContent(container.NewPadded(i.showUnlockView(func() {
+		i.Window.SetContent(previous)
+	})))
+}
+
+func (i *index) lockButton() *widget.Button {
+	return widget.NewButtonWithIcon("Lock", theme.VisibilityOffIcon(), i.lock)
+}
+
+// setupAutoLock locks the app once it has been out of the foreground for the
+// configured time. Coming back earlier cancels the timer.
+func (i *index) setupAutoLock() {
+	lifecycle := i.app.Lifecycle()
+	lifecycle.SetOnExitedForeground(func() {
+		i.stopAutoLockTimer()
+		if i.nodeConfig.autoLockMinutes <= 0 || !i.vaultUnlocked() {
+			return
+		}
+		i.autoLockTimer = time.AfterFunc(time.Duration(i.nodeConfig.autoLockMinutes)*time.Minute, func() {
+			fyne.Do(i.lock)
+		})
+	})
+	lifecycle.SetOnEnteredForeground(i.stopAutoLockTimer)
+}
+
+func (i *index) stopAutoLockTimer() {
+	if i.autoLockTimer != nil {
+		i.autoLockTimer.Stop()
+		i.autoLockTimer = nil
+	}
+}
+
+func (i *index) getAutoLockSelect() *widget.Select {
+	names := make([]string, 0, len(autoLockOptions))
+	for _, m := range autoLockOptions {
+		names = append(names, autoLockText(m))
+	}
+	autoLockSelect := widget.NewSelect(names, func(s string) {
+		for _, m := range autoLockOptions {
+			if autoLockText(m) == s {
+				i.nodeConfig.autoLockMinutes = m
+				return
+			}
+		}
+	})
+	autoLockSelect.SetSelected(autoLockText(i.nodeConfig.autoLockMinutes))
+	return autoLockSelect
+}
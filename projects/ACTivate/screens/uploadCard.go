@@ -3,27 +3,19 @@ package screens
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"time"
+
+	"activate/core"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
-	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/postage"
 )
 
-type uploadedItem struct {
-	Name      string
-	Reference string
-	Size      int64
-	Timestamp time.Time
-	Mimetype  string
-}
-
 func (i *index) showUploadCard() *widget.Card {
 	upForm := i.uploadForm()
 	listButton := i.listUploadsButton(fyne.NewSize(200, 100))
@@ -72,7 +64,7 @@ func (i *index) uploadForm() *widget.Form {
 			{Text: "Choose File", Widget: openFileButton},
 		},
 	}
-	upForm.OnSubmit = func() {
+	upload := func(batchID string) {
 		go func() {
 			defer func() {
 				err := pathBind.Set("")
@@ -81,52 +73,29 @@ func (i *index) uploadForm() *widget.Form {
 				}
 				file = nil
 			}()
-			if file == nil {
-				i.showError(fmt.Errorf("please select a file"))
-				return
-			}
-			batchID := i.getPreferenceString(batchPrefKey)
-			if batchID == "" {
-				i.showError(fmt.Errorf("please select a batch of stamp"))
-				return
-			}
 			filename := path.Text
-			i.logger.Log(fmt.Sprintf("stamp selected: %s", batchID))
 			i.showProgressWithMessage(fmt.Sprintf("Uploading %s", filename))
-			ref, _, err := i.bl.AddFileBzz(context.Background(), batchID, filename, mimetype, false, swarm.ZeroAddress, false, 0, file)
+			ref, err := i.svc.Upload(context.Background(), batchID, filename, mimetype, fileSize, false, file)
 			if err != nil {
 				i.hideProgress()
 				i.showError(err)
 				return
 			}
 			i.logger.Log(fmt.Sprintf("reference of the uploaded file: %s", ref.String()))
-			uploadedSrt := i.getPreferenceString(uploadsPrefKey)
-			uploads := []uploadedItem{}
-			if uploadedSrt != "" {
-				err := json.Unmarshal([]byte(uploadedSrt), &uploads)
-				if err != nil {
-					i.showError(err)
-				}
-			}
-			uploads = append(uploads, uploadedItem{
-				Name:      filename,
-				Reference: ref.String(),
-				Timestamp: time.Now(),
-				Size:      fileSize,
-				Mimetype:  mimetype,
-			})
-			data, err := json.Marshal(uploads)
-			if err != nil {
-				i.hideProgress()
-				i.showError(err)
-				return
-			}
-			i.setPreference(uploadsPrefKey, string(data))
 			d := dialog.NewCustomConfirm("Upload successful", "Ok", "Cancel", i.copyDialog(shortenHashOrAddress(ref.String()), ref.String()), func(b bool) {}, i.Window)
 			i.hideProgress()
 			d.Show()
 		}()
 	}
+	upForm.OnSubmit = func() {
+		if file == nil {
+			i.showError(fmt.Errorf("please select a file"))
+			return
+		}
+		i.confirmBatch(batchGroupUploads, fmt.Sprintf("Upload %s", path.Text), func(stamp *postage.StampIssuer) {
+			upload(core.BatchHex(stamp))
+		})
+	}
 
 	return upForm
 }
@@ -135,21 +104,17 @@ func (i *index) listUploadsButton(minSize fyne.Size) *widget.Button {
 	button := widget.NewButton("All Uploads", func() {
 		uploadedContent := container.NewVBox()
 		uploadedContentWrapper := container.NewScroll(uploadedContent)
-		uploadedSrt := i.getPreferenceString(uploadsPrefKey)
-		uploads := []uploadedItem{}
-		if uploadedSrt != "" {
-			err := json.Unmarshal([]byte(uploadedSrt), &uploads)
-			if err != nil {
-				i.showError(err)
-			}
-			for _, v := range uploads {
-				ref := v.Reference
-				name := v.Name
-				label := widget.NewLabel(fmt.Sprintf("%s\n%s", name, shortenHashOrAddress(ref)))
-				label.Wrapping = fyne.TextWrapWord
-				item := container.NewBorder(label, nil, nil, i.copyButton(ref))
-				uploadedContent.Add(item)
-			}
+		uploads, err := i.svc.Uploads()
+		if err != nil {
+			i.showError(err)
+		}
+		for _, v := range uploads {
+			ref := v.Reference
+			name := v.Name
+			label := widget.NewLabel(fmt.Sprintf("%s\n%s", name, shortenHashOrAddress(ref)))
+			label.Wrapping = fyne.TextWrapWord
+			item := container.NewBorder(label, nil, nil, i.copyButton(ref))
+			uploadedContent.Add(item)
 		}
 
 		if len(uploads) == 0 {
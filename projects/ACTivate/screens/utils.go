@@ -1,10 +1,13 @@
 package screens
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"runtime/debug"
 
+	"activate/core"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
@@ -81,7 +84,7 @@ func (i *index) hideProgress() {
 }
 
 func (i *index) showError(err error) {
-	label := widget.NewLabel(err.Error())
+	label := widget.NewLabel(errorMessage(err))
 	label.Wrapping = fyne.TextWrapWord
 	d := dialog.NewCustom("Error", "       Close       ", label, i.Window)
 	parentSize := i.Window.Canvas().Size()
@@ -97,7 +100,7 @@ func (i *index) copyButton(s string) *widget.Button {
 
 func (i *index) showErrorWithAddr(addr common.Address, err error) {
 	header := container.NewHBox(widget.NewLabel(shortenHashOrAddress(addr.String())), i.copyButton(addr.String()))
-	label := widget.NewLabel(err.Error())
+	label := widget.NewLabel(errorMessage(err))
 	label.Wrapping = fyne.TextWrapWord
 	content := container.NewBorder(header, label, nil, nil)
 	d := dialog.NewCustom("Error", "       Close       ", content, i.Window)
@@ -106,6 +109,16 @@ func (i *index) showErrorWithAddr(addr common.Address, err error) {
 	d.Show()
 }
 
+// errorMessage puts the reason of a contract revert first, ahead of the
+// errors it is wrapped in.
+func errorMessage(err error) string {
+	var revertErr *core.RevertError
+	if errors.As(err, &revertErr) {
+		return fmt.Sprintf("The contract refused the transaction: %s\n\n%s", revertErr.Reason, err.Error())
+	}
+	return err.Error()
+}
+
 func shortenHashOrAddress(item string) string {
 	return fmt.Sprintf("%s[...]%s", item[0:6], item[len(item)-6:])
 }
@@ -114,6 +127,15 @@ func (i *index) copyDialog(info, data string) fyne.CanvasObject {
 	return container.NewStack(container.NewBorder(nil, nil, nil, i.copyButton(data), widget.NewLabel(info)))
 }
 
+// preferences returns the app preferences for the core service, which keeps
+// nothing with the in-memory keystore of the browser.
+func (i *index) preferences() core.Preferences {
+	if i.nodeConfig.isKeyStoreMem {
+		return core.NopPreferences{}
+	}
+	return i.app.Preferences()
+}
+
 func (i *index) getPreferenceString(key string) string {
 	if !i.nodeConfig.isKeyStoreMem {
 		return i.app.Preferences().String(key)
@@ -128,6 +150,26 @@ func (i *index) getPreferenceBool(key string) bool {
 	return false
 }
 
+func (i *index) getPreferenceBoolWithFallback(key string, fallback bool) bool {
+	if !i.nodeConfig.isKeyStoreMem {
+		return i.app.Preferences().BoolWithFallback(key, fallback)
+	}
+	return fallback
+}
+
+func (i *index) getPreferenceIntWithFallback(key string, fallback int) int {
+	if !i.nodeConfig.isKeyStoreMem {
+		return i.app.Preferences().IntWithFallback(key, fallback)
+	}
+	return fallback
+}
+
+func (i *index) removePreference(key string) {
+	if !i.nodeConfig.isKeyStoreMem {
+		i.app.Preferences().RemoveValue(key)
+	}
+}
+
 func (i *index) setPreference(key string, value interface{}) {
 	if !i.nodeConfig.isKeyStoreMem {
 		switch valueType := value.(type) {
@@ -0,0 +1,249 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"activate/core"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethersphere/bee/v2/pkg/config"
+	"github.com/ethersphere/go-sw3-abi/sw3abi"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// chequebookDeployGas is the gas bee reserves for deploying the
+	// chequebook, it refuses to start in light mode with less.
+	chequebookDeployGas = 250000
+	walletQueryTimeout  = 15 * time.Second
+)
+
+// minSwarmBalance is the xBZZ needed before switching to light mode, enough
+// for a small postage batch.
+var minSwarmBalance = big.NewInt(1e15)
+
+// walletBalances are the funds of the node wallet. swarm and chequebook are
+// nil when they cannot be known on this network or in this node mode.
+type walletBalances struct {
+	native     *big.Int
+	minNative  *big.Int
+	swarm      *big.Int
+	chequebook *big.Int
+}
+
+// missing lists the tokens below the light mode threshold.
+func (w *walletBalances) missing() []string {
+	var missing []string
+	if w.native.Cmp(w.minNative) < 0 {
+		missing = append(missing, fmt.Sprintf("%s %s", formatEther(w.minNative), NativeTokenSymbol))
+	}
+	if w.swarm != nil && w.swarm.Cmp(minSwarmBalance) < 0 {
+		missing = append(missing, fmt.Sprintf("%s %s", formatBZZ(minSwarmBalance), SwarmTokenSymbol))
+	}
+	return missing
+}
+
+func (w *walletBalances) String() string {
+	lines := []string{fmt.Sprintf("%s: %s", NativeTokenSymbol, formatEther(w.native))}
+	if w.swarm != nil {
+		lines = append(lines, fmt.Sprintf("%s: %s", SwarmTokenSymbol, formatBZZ(w.swarm)))
+	}
+	if w.chequebook != nil {
+		lines = append(lines, fmt.Sprintf("Chequebook: %s %s", formatBZZ(w.chequebook), SwarmTokenSymbol))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatEther(wei *big.Int) string {
+	f := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
+	return f.Text('f', 4)
+}
+
+// queryWalletBalances reads the native and xBZZ balances of address. The xBZZ
+// token is looked up from the postage contract of the chain.
+func queryWalletBalances(ctx context.Context, client *ethclient.Client, chainID int64, address common.Address) (*walletBalances, error) {
+	native, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("native balance: %w", err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gas price: %w", err)
+	}
+	w := &walletBalances{
+		native:    native,
+		minNative: new(big.Int).Mul(gasPrice, big.NewInt(chequebookDeployGas)),
+	}
+
+	cfg, ok := config.GetByChainID(chainID)
+	if !ok {
+		return w, nil
+	}
+	postageABI, err := abi.JSON(strings.NewReader(cfg.PostageStampABI))
+	if err != nil {
+		return nil, err
+	}
+	erc20ABI, err := abi.JSON(strings.NewReader(sw3abi.ERC20ABIv0_6_5))
+	if err != nil {
+		return nil, err
+	}
+	values, err := callContract(ctx, client, cfg.PostageStampAddress, postageABI, "bzzToken")
+	if err != nil {
+		return nil, err
+	}
+	token, ok := values[0].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("bzzToken: unexpected result %T", values[0])
+	}
+	values, err = callContract(ctx, client, token, erc20ABI, "balanceOf", address)
+	if err != nil {
+		return nil, err
+	}
+	if w.swarm, err = toBigInt(values[0]); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func callContract(ctx context.Context, client *ethclient.Client, to common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	callData, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: callData}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	values, err := contractABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s: empty result", method)
+	}
+	return values, nil
+}
+
+// walletAddress is the overlay address of the running node, or the one saved
+// by an earlier start.
+func (i *index) walletAddress() (common.Address, bool) {
+	if i.bl != nil {
+		return i.bl.OverlayEthAddress(), true
+	}
+	overlayAddr := i.getPreferenceString(overlayAddrPrefKey)
+	if !common.IsHexAddress(overlayAddr) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(overlayAddr), true
+}
+
+func (i *index) walletRPCEndpoint() string {
+	if i.nodeConfig.rpcEndpoint != "" {
+		return i.nodeConfig.rpcEndpoint
+	}
+	return i.networkProfile().RPCEndpoint
+}
+
+func (i *index) loadWalletBalances() (*walletBalances, error) {
+	address, ok := i.walletAddress()
+	if !ok {
+		return nil, fmt.Errorf("the wallet address is not known until the node started once")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), walletQueryTimeout)
+	defer cancel()
+
+	profile := i.networkProfile()
+	client, err := core.DialHealthyRPC(ctx, i.walletRPCEndpoint(), profile.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	w, err := queryWalletBalances(ctx, client, profile.ChainID, address)
+	if err != nil {
+		return nil, err
+	}
+	if i.bl != nil && i.nodeConfig.swapEnable {
+		if balance, err := i.bl.ChequebookBalance(); err == nil {
+			w.chequebook = balance
+		} else {
+			i.logger.Log(fmt.Sprintf("failed to get chequebook balance: %s", err.Error()))
+		}
+	}
+	return w, nil
+}
+
+// requireFundedWallet returns an error listing the missing funds when the
+// wallet cannot run a light node yet.
+func (i *index) requireFundedWallet() error {
+	w, err := i.loadWalletBalances()
+	if err != nil {
+		return fmt.Errorf("cannot check the wallet balance: %w", err)
+	}
+	if missing := w.missing(); len(missing) != 0 {
+		return fmt.Errorf("cannot continue in light mode, the wallet needs at least %s", strings.Join(missing, " and "))
+	}
+	return nil
+}
+
+// walletContent shows the wallet address with a funding QR code and the
+// balances, loaded in the background.
+func (i *index) walletContent() fyne.CanvasObject {
+	address, ok := i.walletAddress()
+	if !ok {
+		return widget.NewLabel("Start the node once to create its wallet")
+	}
+
+	// EIP-681 payment request, understood by most wallet apps
+	uri := fmt.Sprintf("ethereum:%s@%d", address.Hex(), i.networkProfile().ChainID)
+	content := container.NewVBox(
+		container.NewHBox(widget.NewLabel(shortenHashOrAddress(address.Hex())), i.copyButton(address.Hex())),
+	)
+	if code, err := qrcode.New(uri, qrcode.Medium); err != nil {
+		i.logger.Log(fmt.Sprintf("failed to encode wallet QR code: %s", err.Error()))
+	} else {
+		// 8 pixels per module
+		qr := canvas.NewImageFromImage(code.Image(-8))
+		qr.FillMode = canvas.ImageFillContain
+		qr.ScaleMode = canvas.ImageScalePixels
+		qr.SetMinSize(fyne.NewSize(180, 180))
+		content.Add(qr)
+	}
+
+	balanceLabel := widget.NewLabel("")
+	refresh := func() {
+		balanceLabel.SetText("Loading balances...")
+		go func() {
+			text := ""
+			w, err := i.loadWalletBalances()
+			if err != nil {
+				i.logger.Log(fmt.Sprintf("failed to load wallet balances: %s", err.Error()))
+				text = "Balances unavailable"
+			} else {
+				text = w.String()
+				if missing := w.missing(); len(missing) != 0 {
+					text += fmt.Sprintf("\nLight mode needs at least %s", strings.Join(missing, " and "))
+				}
+			}
+			fyne.Do(func() { balanceLabel.SetText(text) })
+		}()
+	}
+	refresh()
+	content.Add(balanceLabel)
+	content.Add(widget.NewButton("Refresh balances", refresh))
+	return content
+}
+
+func (i *index) showWalletCard() *widget.Card {
+	return widget.NewCard("Wallet", "fund the node for light mode", i.walletContent())
+}
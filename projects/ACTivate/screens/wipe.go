@@ -0,0 +1,219 @@
+package screens
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"activate/core"
+	"activate/localapi"
+	"activate/secrets"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	duressPrefKey = "duress"
+	// wipePendingPrefKey marks a wipe that has to remove the data dir on the
+	// next launch, because the node still held it open.
+	wipePendingPrefKey = "wipePending"
+)
+
+// wipePrefKeys lists every preference the app writes: the ones of the core
+// and those only the GUI keeps.
+var wipePrefKeys = append(slices.Clone(core.PrefKeys),
+	passwordPrefKey,
+	autoLockPrefKey,
+	duressPrefKey,
+)
+
+// wipe stops the node, destroys the local state and quits. The node stops
+// off the UI thread, then the secrets and the rest of the data dir are
+// removed even if it did not stop in time. A node still running may write to
+// the data dir again, so the removal is repeated on the next launch.
+func (i *index) wipe() {
+	i.logger.Log("Wiping local data")
+	i.showProgressWithMessage("Wiping local data")
+	for _, key := range wipePrefKeys {
+		i.removePreference(key)
+	}
+	stop := false
+	i.shutdownOnce.Do(func() {
+		i.stopUI("wipe")
+		stop = true
+	})
+	i.lockVault()
+	i.vault = nil
+	dir := ""
+	if !i.nodeConfig.isKeyStoreMem {
+		dir = i.nodeConfig.path
+	}
+
+	go func() {
+		if stop {
+			i.stopNode()
+		}
+		if dir != "" {
+			if i.svc.Node() != nil {
+				i.logger.Log("The node did not stop, the data dir is removed again on the next launch")
+				i.app.Preferences().SetBool(wipePendingPrefKey, true)
+			}
+			if err := wipeDataDir(dir); err != nil {
+				i.logger.Log(fmt.Sprintf("wipe incomplete: %s", err.Error()))
+			}
+		}
+		fyne.Do(i.app.Quit)
+	}()
+}
+
+// finishWipe removes the data dir left by a wipe while the node was running.
+// It runs on launch, before a node is started.
+func (i *index) finishWipe() {
+	if !i.getPreferenceBool(wipePendingPrefKey) {
+		return
+	}
+	i.logger.Log("Finishing the wipe of the data dir")
+	if err := wipeDataDir(i.nodeConfig.path); err != nil {
+		i.logger.Log(fmt.Sprintf("wipe incomplete: %s", err.Error()))
+		return
+	}
+	i.removePreference(wipePendingPrefKey)
+}
+
+// wipeDataDir overwrites the keys and the vault before removing everything in
+// dir. Overwriting is best effort, flash storage may keep old blocks around.
+func wipeDataDir(dir string) error {
+	return errors.Join(shredSecrets(dir), removeDataDir(dir))
+}
+
+// shredSecrets overwrites and removes the keys, the vault and the other
+// secrets in dir. The node does not keep them open.
+func shredSecrets(dir string) error {
+	var errs []error
+	for _, name := range []string{secrets.FileName, keysDir, core.DeploymentsDir, localapi.TokenFile} {
+		if err := shredPath(filepath.Join(dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeDataDir removes everything in dir.
+func removeDataDir(dir string) error {
+	var errs []error
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func shredPath(path string) error {
+	return filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			return shredFile(p, info.Size())
+		}
+		return nil
+	})
+}
+
+func shredFile(path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, rand.Reader, size); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (i *index) isDuressPassphrase(passphrase string) bool {
+	hash := i.getPreferenceString(duressPrefKey)
+	return hash != "" && secrets.MatchPassphrase(passphrase, hash)
+}
+
+func (i *index) wipeButton() *widget.Button {
+	button := widget.NewButton("Wipe all data", func() {
+		dialog.NewConfirm("Wipe all data",
+			"Keys, vault, local store and settings are destroyed and the app quits.\nThis cannot be undone.",
+			func(ok bool) {
+				if ok {
+					i.wipe()
+				}
+			}, i.Window).Show()
+	})
+	button.Importance = widget.DangerImportance
+
+	return button
+}
+
+func (i *index) duressButton() *widget.Button {
+	return widget.NewButton("Set duress passphrase", func() {
+		passphraseEntry := widget.NewPasswordEntry()
+		passphraseEntry.SetPlaceHolder("Leave empty to disable")
+		d := dialog.NewForm("Duress passphrase", "Save", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("Passphrase", passphraseEntry)},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if passphraseEntry.Text == "" {
+					i.removePreference(duressPrefKey)
+					i.logger.Log("Duress passphrase disabled")
+					return
+				}
+				if i.vaultPassphraseMatches(passphraseEntry.Text) {
+					i.showError(fmt.Errorf("the duress passphrase must differ from the unlock passphrase"))
+					return
+				}
+				i.showProgressWithMessage("Saving")
+				hash, err := secrets.HashPassphrase(passphraseEntry.Text)
+				i.hideProgress()
+				if err != nil {
+					i.showError(err)
+					return
+				}
+				i.setPreference(duressPrefKey, hash)
+				i.logger.Log("Duress passphrase set")
+			}, i.Window)
+		d.Show()
+	})
+}
+
+func (i *index) vaultPassphraseMatches(passphrase string) bool {
+	if !i.hasVault() {
+		return false
+	}
+	vault, err := secrets.Open(i.vaultPath(), passphrase)
+	if err != nil {
+		return false
+	}
+	vault.Lock()
+	return true
+}
@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+)
+
+type passphraseHash struct {
+	KDF  kdfParams `json:"kdf"`
+	Hash []byte    `json:"hash"`
+}
+
+// HashPassphrase returns an encoded Argon2id hash of the passphrase, for
+// passphrases that only have to be recognised, never recovered.
+func HashPassphrase(passphrase string) (string, error) {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return "", err
+	}
+	kdf, err := newKDFParams()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(passphraseHash{KDF: kdf, Hash: deriveKey(passphrase, kdf)})
+	if err != nil {
+		return "", fmt.Errorf("encode passphrase hash: %w", err)
+	}
+	return string(data), nil
+}
+
+// MatchPassphrase reports whether the passphrase matches a hash returned by
+// HashPassphrase.
+func MatchPassphrase(passphrase, encoded string) bool {
+	var h passphraseHash
+	if err := json.Unmarshal([]byte(encoded), &h); err != nil || h.KDF.validate() != nil {
+		return false
+	}
+	key := deriveKey(passphrase, h.KDF)
+	defer wipe(key)
+	return subtle.ConstantTimeCompare(key, h.Hash) == 1
+}
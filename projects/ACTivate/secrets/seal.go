@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Seal encrypts data with a key derived from the passphrase. The result is
+// self-contained and uses the same format as the vault file.
+func Seal(passphrase string, data []byte) ([]byte, error) {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	kdf, err := newKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	key := deriveKey(passphrase, kdf)
+	defer wipe(key)
+
+	f, err := seal(key, kdf, data)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode sealed data: %w", err)
+	}
+	return out, nil
+}
+
+// Unseal decrypts data produced by Seal.
+func Unseal(passphrase string, data []byte) ([]byte, error) {
+	f, err := decodeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	key := deriveKey(passphrase, f.KDF)
+	defer wipe(key)
+	return open(key, *f)
+}
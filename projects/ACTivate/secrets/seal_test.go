@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealUnseal(t *testing.T) {
+	data := []byte("node password")
+	sealed, err := Seal("123456", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, data) {
+		t.Error("sealed data contains the plaintext")
+	}
+	got, err := Unseal("123456", sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("unsealed %q, want %q", got, data)
+	}
+
+	if _, err := Unseal("654321", sealed); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("unseal with a wrong passphrase: %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := Seal("12345", data); err == nil {
+		t.Error("sealed with a too short passphrase")
+	}
+}
+
+func TestUnsealBadParams(t *testing.T) {
+	sealed, err := Seal("123456", []byte("node password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, change := range map[string]func(p *kdfParams){
+		"name":        func(p *kdfParams) { p.Name = "scrypt" },
+		"no threads":  func(p *kdfParams) { p.Threads = 0 },
+		"no time":     func(p *kdfParams) { p.Time = 0 },
+		"long time":   func(p *kdfParams) { p.Time = maxKDFTime + 1 },
+		"no memory":   func(p *kdfParams) { p.Memory = 0 },
+		"huge memory": func(p *kdfParams) { p.Memory = maxKDFMemory + 1 },
+		"short salt":  func(p *kdfParams) { p.Salt = p.Salt[:8] },
+		"long salt":   func(p *kdfParams) { p.Salt = append(p.Salt, 0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var f vaultFile
+			if err := json.Unmarshal(sealed, &f); err != nil {
+				t.Fatal(err)
+			}
+			change(&f.KDF)
+			data, err := json.Marshal(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Unseal("123456", data); err == nil || errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("unseal: %v, want a parameter error", err)
+			}
+		})
+	}
+
+	if _, err := Unseal("123456", []byte(strings.Replace(string(sealed), `"version": 1`, `"version": 2`, 1))); err == nil {
+		t.Error("unsealed an unsupported version")
+	}
+}
+
+func TestMatchPassphrase(t *testing.T) {
+	hash, err := HashPassphrase("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !MatchPassphrase("123456", hash) {
+		t.Error("the passphrase does not match its hash")
+	}
+	if MatchPassphrase("654321", hash) {
+		t.Error("a wrong passphrase matches")
+	}
+}
@@ -0,0 +1,412 @@
+// Package secrets keeps sensitive values, such as the node password, in an
+// encrypted file that is unlocked with a user passphrase or PIN.
+//
+// The encryption key is derived from the passphrase with Argon2id and the
+// secrets are sealed with AES-256-GCM. Nothing is written to disk in clear.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// FileName is the name of the vault file inside the app data directory.
+	FileName = "vault.json"
+
+	// MinPassphraseLength is the shortest accepted passphrase, long enough for
+	// a six digit PIN.
+	MinPassphraseLength = 6
+
+	fileVersion = 1
+	kdfArgon2id = "argon2id"
+	keyLength   = 32
+	saltLength  = 16
+
+	// Bounds of the Argon2id parameters read from a file, so a crafted file
+	// cannot make unlocking take minutes or exhaust memory.
+	maxKDFTime   = 10
+	maxKDFMemory = 1024 * 1024 // KiB
+)
+
+var (
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	ErrLocked          = errors.New("vault is locked")
+	ErrNotFound        = errors.New("secret not found")
+)
+
+type kdfParams struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+type vaultFile struct {
+	Version    int       `json:"version"`
+	KDF        kdfParams `json:"kdf"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// Vault is a set of named secrets. An unlocked vault keeps the derived key and
+// the decrypted secrets in memory until Lock is called.
+type Vault struct {
+	mu      sync.Mutex
+	path    string
+	kdf     kdfParams
+	key     []byte
+	secrets map[string][]byte
+}
+
+// ValidatePassphrase checks the passphrase is acceptable for a new vault.
+func ValidatePassphrase(passphrase string) error {
+	if len(passphrase) < MinPassphraseLength {
+		return fmt.Errorf("passphrase must be at least %d characters", MinPassphraseLength)
+	}
+	return nil
+}
+
+// Exists reports whether a vault file is present at path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Create creates a new, empty vault at path. It refuses to overwrite an
+// existing vault.
+func Create(path, passphrase string) (*Vault, error) {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if Exists(path) {
+		return nil, fmt.Errorf("create vault: %w", os.ErrExist)
+	}
+
+	kdf, err := newKDFParams()
+	if err != nil {
+		return nil, err
+	}
+	v := &Vault{
+		path:    path,
+		kdf:     kdf,
+		key:     deriveKey(passphrase, kdf),
+		secrets: make(map[string][]byte),
+	}
+	if err := v.save(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NewMemory returns an unlocked vault that is never written to disk.
+func NewMemory() *Vault {
+	return &Vault{
+		key:     make([]byte, keyLength),
+		secrets: make(map[string][]byte),
+	}
+}
+
+// Open reads the vault at path and unlocks it with the passphrase.
+func Open(path, passphrase string) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vault: %w", err)
+	}
+
+	f, err := decodeFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := deriveKey(passphrase, f.KDF)
+	plaintext, err := open(key, *f)
+	if err != nil {
+		wipe(key)
+		return nil, err
+	}
+	defer wipe(plaintext)
+
+	secrets := make(map[string][]byte)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		wipe(key)
+		return nil, fmt.Errorf("decode secrets: %w", err)
+	}
+
+	return &Vault{
+		path:    path,
+		kdf:     f.KDF,
+		key:     key,
+		secrets: secrets,
+	}, nil
+}
+
+// Get returns a copy of the named secret.
+func (v *Vault) Get(name string) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.key == nil {
+		return nil, ErrLocked
+	}
+	value, ok := v.secrets[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Set stores the named secret and writes the vault.
+func (v *Vault) Set(name string, value []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.key == nil {
+		return ErrLocked
+	}
+	if old, ok := v.secrets[name]; ok {
+		wipe(old)
+	}
+	v.secrets[name] = append([]byte(nil), value...)
+	return v.save()
+}
+
+// Delete removes the named secret and writes the vault.
+func (v *Vault) Delete(name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.key == nil {
+		return ErrLocked
+	}
+	if old, ok := v.secrets[name]; ok {
+		wipe(old)
+		delete(v.secrets, name)
+	}
+	return v.save()
+}
+
+// ChangePassphrase re-encrypts the vault with a key derived from a new
+// passphrase and a fresh salt.
+func (v *Vault) ChangePassphrase(passphrase string) error {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.key == nil {
+		return ErrLocked
+	}
+	kdf, err := newKDFParams()
+	if err != nil {
+		return err
+	}
+	oldKDF, oldKey := v.kdf, v.key
+	v.kdf, v.key = kdf, deriveKey(passphrase, kdf)
+	if err := v.save(); err != nil {
+		wipe(v.key)
+		v.kdf, v.key = oldKDF, oldKey
+		return err
+	}
+	wipe(oldKey)
+	return nil
+}
+
+// Lock wipes the key and the decrypted secrets from memory.
+func (v *Vault) Lock() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	wipe(v.key)
+	v.key = nil
+	for name, value := range v.secrets {
+		wipe(value)
+		delete(v.secrets, name)
+	}
+}
+
+// Locked reports whether the vault has been locked.
+func (v *Vault) Locked() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.key == nil
+}
+
+// save encrypts the secrets and atomically replaces the vault file. The
+// caller must hold the lock.
+func (v *Vault) save() error {
+	if v.path == "" {
+		return nil
+	}
+
+	plaintext, err := json.Marshal(v.secrets)
+	if err != nil {
+		return fmt.Errorf("encode secrets: %w", err)
+	}
+	defer wipe(plaintext)
+
+	f, err := seal(v.key, v.kdf, plaintext)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode vault: %w", err)
+	}
+	return writeFileAtomic(v.path, data)
+}
+
+func decodeFile(data []byte) (*vaultFile, error) {
+	var f vaultFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode vault: %w", err)
+	}
+	if f.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported vault version %d", f.Version)
+	}
+	if err := f.KDF.validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// validate checks parameters read from a file before a key is derived with
+// them.
+func (p kdfParams) validate() error {
+	switch {
+	case p.Name != kdfArgon2id:
+		return fmt.Errorf("unsupported key derivation %q", p.Name)
+	case len(p.Salt) != saltLength:
+		return fmt.Errorf("invalid key derivation salt length %d", len(p.Salt))
+	case p.Threads == 0:
+		return errors.New("invalid key derivation threads 0")
+	case p.Time == 0 || p.Time > maxKDFTime:
+		return fmt.Errorf("key derivation time %d out of range", p.Time)
+	case p.Memory < 8*uint32(p.Threads) || p.Memory > maxKDFMemory:
+		return fmt.Errorf("key derivation memory %d KiB out of range", p.Memory)
+	}
+	return nil
+}
+
+func newKDFParams() (kdfParams, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return kdfParams{}, fmt.Errorf("generate salt: %w", err)
+	}
+	// RFC 9106 second recommended option, which stays usable on phones.
+	return kdfParams{
+		Name:    kdfArgon2id,
+		Salt:    salt,
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}, nil
+}
+
+func deriveKey(passphrase string, p kdfParams) []byte {
+	return argon2.IDKey([]byte(passphrase), p.Salt, p.Time, p.Memory, p.Threads, keyLength)
+}
+
+// additionalData binds the ciphertext to the file header, so the KDF
+// parameters cannot be swapped without failing authentication.
+func additionalData(version int, p kdfParams) ([]byte, error) {
+	return json.Marshal(struct {
+		Version int       `json:"version"`
+		KDF     kdfParams `json:"kdf"`
+	}{version, p})
+}
+
+func seal(key []byte, p kdfParams, plaintext []byte) (*vaultFile, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	ad, err := additionalData(fileVersion, p)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultFile{
+		Version:    fileVersion,
+		KDF:        p,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, ad),
+	}, nil
+}
+
+func open(key []byte, f vaultFile) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid vault nonce")
+	}
+	ad, err := additionalData(f.Version, f.KDF)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, ad)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}